@@ -0,0 +1,70 @@
+package sqlcraft
+
+import (
+	"context"
+	"time"
+)
+
+// Audit defines the audit columns of a table, InsertQuery fills the created and updated columns
+// and UpdateQuery fills the updated columns, a column with an empty name is not filled
+type Audit struct {
+	CreatedAt string
+	UpdatedAt string
+	CreatedBy string
+	UpdatedBy string
+
+	// Clock returns the time of the *_at columns, time.Now is used when nil
+	Clock func() time.Time
+	// Actor returns the actor of the *_by columns from the query context,
+	// when it returns false the *_by columns are not filled
+	Actor func(ctx context.Context) (any, bool)
+}
+
+func (a Audit) now() time.Time {
+	if a.Clock == nil {
+		return time.Now()
+	}
+
+	return a.Clock()
+}
+
+func (a Audit) actor(ctx context.Context) (any, bool) {
+	if a.Actor == nil {
+		return nil, false
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return a.Actor(ctx)
+}
+
+// auditColumn is a column filled by the audit with its value
+type auditColumn struct {
+	column string
+	value  any
+}
+
+// insertColumns returns the audit columns of a new row
+func (a Audit) insertColumns(ctx context.Context) []auditColumn {
+	now := a.now()
+	columns := []auditColumn{{a.CreatedAt, now}, {a.UpdatedAt, now}}
+
+	if actor, ok := a.actor(ctx); ok {
+		columns = append(columns, auditColumn{a.CreatedBy, actor}, auditColumn{a.UpdatedBy, actor})
+	}
+
+	return columns
+}
+
+// updateColumns returns the audit columns of an updated row
+func (a Audit) updateColumns(ctx context.Context) []auditColumn {
+	columns := []auditColumn{{a.UpdatedAt, a.now()}}
+
+	if actor, ok := a.actor(ctx); ok {
+		columns = append(columns, auditColumn{a.UpdatedBy, actor})
+	}
+
+	return columns
+}
@@ -0,0 +1,89 @@
+package sqlcraft
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/techforge-lat/dafi/v2"
+)
+
+type actorKey struct{}
+
+func TestAudit(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)
+	audit := Audit{
+		CreatedAt: "created_at",
+		UpdatedAt: "updated_at",
+		CreatedBy: "created_by",
+		UpdatedBy: "updated_by",
+		Clock:     func() time.Time { return now },
+		Actor: func(ctx context.Context) (any, bool) {
+			actor, ok := ctx.Value(actorKey{}).(string)
+
+			return actor, ok
+		},
+	}
+	ctx := context.WithValue(context.Background(), actorKey{}, "admin")
+
+	tests := []struct {
+		name    string
+		query   interface{ ToSQL() (Result, error) }
+		want    Result
+		wantErr bool
+	}{
+		{
+			name:  "insert fills every audit column of every row",
+			query: InsertInto("users").WithColumns("email").WithValues("hernan_rm@outlook.es").WithValues("brownie@gmail.com").WithAudit(audit).WithContext(ctx),
+			want: Result{
+				Sql:  "INSERT INTO users (email, created_at, updated_at, created_by, updated_by) VALUES ($1, $2, $3, $4, $5), ($6, $7, $8, $9, $10)",
+				Args: []any{"hernan_rm@outlook.es", now, now, "admin", "admin", "brownie@gmail.com", now, now, "admin", "admin"},
+			},
+		},
+		{
+			name:  "insert without actor keeps provided columns",
+			query: InsertInto("users").WithColumns("email", "created_at").WithValues("hernan_rm@outlook.es", nil).WithAudit(audit),
+			want: Result{
+				Sql:  "INSERT INTO users (email, created_at, updated_at) VALUES ($1, $2, $3)",
+				Args: []any{"hernan_rm@outlook.es", nil, now},
+			},
+		},
+		{
+			name:  "update fills updated audit columns",
+			query: Update("users").Set("email", "brownie@gmail.com").Where(dafi.Filter{Field: "id", Value: 1}).WithAudit(audit).WithContext(ctx),
+			want: Result{
+				Sql:  "UPDATE users SET email = $1, updated_at = $2, updated_by = $3 WHERE id = $4",
+				Args: []any{"brownie@gmail.com", now, "admin", 1},
+			},
+		},
+		{
+			name:  "partial update of placeholder columns overrides audit columns",
+			query: Update("users").WithColumns("email", "name").WithPartialUpdate().Where(dafi.Filter{Field: "id", Value: 1}).WithAudit(audit).WithContext(ctx),
+			want: Result{
+				Sql:  "UPDATE users SET email = COALESCE($1, email), name = COALESCE($2, name), updated_at = $3, updated_by = $4 WHERE id = $5",
+				Args: []any{now, "admin", 1},
+			},
+		},
+		{
+			name:    "update with nothing to set",
+			query:   Update("users").Set("email", Optional[string]{}).Where(dafi.Filter{Field: "id", Value: 1}).WithAudit(audit).WithContext(ctx),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.query.ToSQL()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ToSQL() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ToSQL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bytes"
+	"go/format"
+	"text/template"
+)
+
+// tableSpec is a table to generate
+type tableSpec struct {
+	// GoName prefixes every generated identifier of the table
+	GoName           string
+	Name             string
+	Columns          []columnSpec
+	SoftDeleteColumn string
+}
+
+type columnSpec struct {
+	GoName string
+	Name   string
+	// DomainField is empty when the field is hidden from the clients
+	DomainField string
+	// Type is the name of a sqlcraft ColumnType constant, empty when unknown
+	Type       string
+	PrimaryKey bool
+}
+
+func (t tableSpec) PrimaryKey() []columnSpec {
+	var columns []columnSpec
+	for _, column := range t.Columns {
+		if column.PrimaryKey {
+			columns = append(columns, column)
+		}
+	}
+
+	return columns
+}
+
+func (t tableSpec) TypedColumns() []columnSpec {
+	var columns []columnSpec
+	for _, column := range t.Columns {
+		if column.Type != "" {
+			columns = append(columns, column)
+		}
+	}
+
+	return columns
+}
+
+var codeTemplate = template.Must(template.New("code").Parse(`// Code generated by sqlcraft-gen. DO NOT EDIT.
+
+package {{ .Package }}
+
+import "github.com/techforge-lat/sqlcraft"
+{{ range .Tables }}{{ $table := . }}
+const (
+	{{ .GoName }}TableName = "{{ .Name }}"
+{{ range .Columns }}
+	{{ $table.GoName }}Column{{ .GoName }} = "{{ .Name }}"{{ end }}
+)
+
+// {{ .GoName }}Columns are the columns of the {{ .Name }} table
+var {{ .GoName }}Columns = []string{ {{- range $i, $c := .Columns }}{{ if $i }}, {{ end }}{{ $table.GoName }}Column{{ $c.GoName }}{{ end -}} }
+
+// {{ .GoName }}SQLColumnByDomainField maps the {{ .GoName }} domain fields to the {{ .Name }} columns
+var {{ .GoName }}SQLColumnByDomainField = map[string]string{
+{{- range .Columns }}{{ if .DomainField }}
+	"{{ .DomainField }}": {{ $table.GoName }}Column{{ .GoName }},{{ end }}{{ end }}
+}
+
+// {{ .GoName }}ColumnTypes are the types of the {{ .Name }} columns
+var {{ .GoName }}ColumnTypes = sqlcraft.ColumnTypes{
+{{- range .TypedColumns }}
+	{{ $table.GoName }}Column{{ .GoName }}: sqlcraft.{{ .Type }},{{ end }}
+}
+
+// {{ .GoName }}Table is the definition of the {{ .Name }} table
+var {{ .GoName }}Table = sqlcraft.Table{
+	Name:                   {{ .GoName }}TableName,
+	Columns:                {{ .GoName }}Columns,
+	PrimaryKey:             []string{ {{- range $i, $c := .PrimaryKey }}{{ if $i }}, {{ end }}{{ $table.GoName }}Column{{ $c.GoName }}{{ end -}} },
+	SQLColumnByDomainField: {{ .GoName }}SQLColumnByDomainField,
+	ColumnTypes:            {{ .GoName }}ColumnTypes,
+{{- if .SoftDeleteColumn }}
+	SoftDeleteColumn:       "{{ .SoftDeleteColumn }}",{{ end }}
+}
+
+func Select{{ .GoName }}(columns ...string) sqlcraft.SelectQuery {
+	return {{ .GoName }}Table.Select(columns...)
+}
+
+func InsertInto{{ .GoName }}() sqlcraft.InsertQuery {
+	return {{ .GoName }}Table.InsertInto()
+}
+
+func Update{{ .GoName }}() sqlcraft.UpdateQuery {
+	return {{ .GoName }}Table.Update()
+}
+
+func DeleteFrom{{ .GoName }}() sqlcraft.DeleteQuery {
+	return {{ .GoName }}Table.DeleteFrom()
+}
+{{ end }}`))
+
+// generate returns the formatted code of the tables
+func generate(pkg string, tables []tableSpec) ([]byte, error) {
+	buffer := bytes.Buffer{}
+	err := codeTemplate.Execute(&buffer, struct {
+		Package string
+		Tables  []tableSpec
+	}{
+		Package: pkg,
+		Tables:  tables,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return format.Source(buffer.Bytes())
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const modelsSource = `package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Timestamps struct {
+	CreatedAt time.Time ` + "`json:\"createdAt\" db:\"created_at\"`" + `
+}
+
+//sqlcraft:table users softdelete=deleted_at
+type User struct {
+	ID        uuid.UUID  ` + "`json:\"id\" db:\"id,pk\"`" + `
+	Email     string     ` + "`json:\"email\" db:\"email\"`" + `
+	Password  string     ` + "`json:\"-\"`" + `
+	Token     string     ` + "`json:\"-\" db:\"token\"`" + `
+	DeletedAt *time.Time ` + "`json:\"deletedAt\" db:\"deleted_at\"`" + `
+	Timestamps
+}
+
+type NotATable struct {
+	Name string ` + "`db:\"name\"`" + `
+}
+`
+
+func TestParseStructs(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "models.go"), []byte(modelsSource), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	pkg, tables, err := parseStructs(dir, "json", "sqlcraft_gen.go")
+	if err != nil {
+		t.Fatalf("parseStructs() error = %v", err)
+	}
+
+	if pkg != "models" {
+		t.Errorf("parseStructs() pkg = %v, want models", pkg)
+	}
+
+	want := []tableSpec{
+		{
+			GoName:           "User",
+			Name:             "users",
+			SoftDeleteColumn: "deleted_at",
+			Columns: []columnSpec{
+				{GoName: "ID", Name: "id", DomainField: "id", Type: "UUIDColumnType", PrimaryKey: true},
+				{GoName: "Email", Name: "email", DomainField: "email", Type: "TextColumnType"},
+				{GoName: "Token", Name: "token", Type: "TextColumnType"},
+				{GoName: "DeletedAt", Name: "deleted_at", DomainField: "deletedAt", Type: "TimeColumnType"},
+				{GoName: "CreatedAt", Name: "created_at", DomainField: "createdAt", Type: "TimeColumnType"},
+			},
+		},
+	}
+	if !reflect.DeepEqual(tables, want) {
+		t.Errorf("parseStructs() = %+v, want %+v", tables, want)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	tables := []tableSpec{
+		{
+			GoName: "User",
+			Name:   "users",
+			Columns: []columnSpec{
+				{GoName: "ID", Name: "id", DomainField: "id", Type: "IntColumnType", PrimaryKey: true},
+				{GoName: "Email", Name: "email", DomainField: "email"},
+				{GoName: "Token", Name: "token"},
+			},
+		},
+	}
+
+	code, err := generate("models", tables)
+	if err != nil {
+		t.Fatalf("generate() error = %v", err)
+	}
+
+	wants := []string{
+		"package models",
+		`UserTableName = "users"`,
+		`UserColumnID    = "id"`,
+		"var UserColumns = []string{UserColumnID, UserColumnEmail, UserColumnToken}",
+		`"email": UserColumnEmail,`,
+		"UserColumnID: sqlcraft.IntColumnType,",
+		"PrimaryKey:             []string{UserColumnID},",
+		"func SelectUser(columns ...string) sqlcraft.SelectQuery {",
+		"func InsertIntoUser() sqlcraft.InsertQuery {",
+		"func UpdateUser() sqlcraft.UpdateQuery {",
+		"func DeleteFromUser() sqlcraft.DeleteQuery {",
+	}
+	for _, want := range wants {
+		if !strings.Contains(string(code), want) {
+			t.Errorf("generate() missing %q in\n%s", want, code)
+		}
+	}
+
+	if strings.Contains(string(code), ": UserColumnToken,") {
+		t.Errorf("generate() unexpected hidden column in the domain field mapping\n%s", code)
+	}
+
+	if strings.Contains(string(code), "SoftDeleteColumn") {
+		t.Errorf("generate() unexpected SoftDeleteColumn in\n%s", code)
+	}
+}
+
+func TestParseDirective(t *testing.T) {
+	tests := []struct {
+		name      string
+		directive string
+		want      tableSpec
+		wantErr   bool
+	}{
+		{name: "table name", directive: "users", want: tableSpec{GoName: "User", Name: "users"}},
+		{name: "soft delete", directive: "users softdelete=deleted_at", want: tableSpec{GoName: "User", Name: "users", SoftDeleteColumn: "deleted_at"}},
+		{name: "missing table name", directive: "", wantErr: true},
+		{name: "unknown option", directive: "users audit=true", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseDirective("User", tt.directive)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseDirective() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseDirective() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
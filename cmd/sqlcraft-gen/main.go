@@ -0,0 +1,99 @@
+// Command sqlcraft-gen generates typed table definitions for sqlcraft.
+//
+// It reads the structs of a Go package annotated with a sqlcraft:table directive
+// and generates, per table, the column constants, the column list, the domain field
+// mapping, the column types, a sqlcraft.Table and its Select, InsertInto, Update and
+// DeleteFrom constructors:
+//
+//	//sqlcraft:table users softdelete=deleted_at
+//	type User struct {
+//		ID        uuid.UUID  `json:"id" db:"id,pk"`
+//		Email     string     `json:"email" db:"email"`
+//		DeletedAt *time.Time `json:"deletedAt" db:"deleted_at"`
+//	}
+//
+// With -schema it reads the CREATE TABLE and ALTER TABLE statements of a directory of sql
+// migrations instead, without connecting to a database, the go names are derived from the
+// snake_case table and column names and the domain fields are their camelCase form
+//
+// Usage:
+//
+//	//go:generate sqlcraft-gen -dir . -out tables_gen.go
+//	//go:generate sqlcraft-gen -schema ../migrations -softdelete deleted_at -out tables_gen.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	dir := flag.String("dir", ".", "directory of the Go package to read")
+	out := flag.String("out", "sqlcraft_gen.go", "file to write, relative to dir")
+	domainTag := flag.String("domain-tag", "json", "struct tag with the domain field names")
+	schemaDir := flag.String("schema", "", "directory of sql files to read instead of go structs")
+	pkg := flag.String("package", "", "package of the generated file in schema mode, the dir name by default")
+	softDelete := flag.String("softdelete", "", "soft-delete column of the tables that have it, in schema mode")
+	flag.Parse()
+
+	var err error
+	if *schemaDir != "" {
+		err = runSchema(*schemaDir, *dir, *out, *pkg, *softDelete)
+	} else {
+		err = run(*dir, *out, *domainTag)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "sqlcraft-gen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(dir, out, domainTag string) error {
+	outPath := filepath.Join(dir, out)
+
+	pkg, tables, err := parseStructs(dir, domainTag, filepath.Base(outPath))
+	if err != nil {
+		return err
+	}
+
+	if len(tables) == 0 {
+		return fmt.Errorf("no struct with a %s directive found in %s", tableDirective, dir)
+	}
+
+	return write(outPath, pkg, tables)
+}
+
+func runSchema(schemaDir, dir, out, pkg, softDeleteColumn string) error {
+	tables, err := parseSchema(schemaDir, softDeleteColumn)
+	if err != nil {
+		return err
+	}
+
+	if len(tables) == 0 {
+		return fmt.Errorf("no CREATE TABLE statement found in %s", schemaDir)
+	}
+
+	if pkg == "" {
+		absDir, err := filepath.Abs(dir)
+		if err != nil {
+			return err
+		}
+
+		pkg = strings.ReplaceAll(filepath.Base(absDir), "-", "_")
+	}
+
+	return write(filepath.Join(dir, out), pkg, tables)
+}
+
+func write(path, pkg string, tables []tableSpec) error {
+	code, err := generate(pkg, tables)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, code, 0o644)
+}
@@ -0,0 +1,523 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+var (
+	createTableRegexp = regexp.MustCompile(`(?is)^create\s+(?:(?:global|local)\s+)?(?:(?:temp|temporary|unlogged)\s+)?table\s+(?:if\s+not\s+exists\s+)?([^\s(]+)\s*\((.*)\)`)
+	alterTableRegexp  = regexp.MustCompile(`(?is)^alter\s+table\s+(?:if\s+exists\s+)?(?:only\s+)?([^\s]+)\s+(.*)$`)
+	dropTableRegexp   = regexp.MustCompile(`(?is)^drop\s+table\s+(?:if\s+exists\s+)?(.*?)(?:\s+(?:cascade|restrict))?$`)
+	primaryKeyRegexp  = regexp.MustCompile(`(?is)^(?:constraint\s+\S+\s+)?primary\s+key\s*\((.*)\)`)
+)
+
+// columnConstraintWords end the type of a column definition
+var columnConstraintWords = map[string]struct{}{
+	"not": {}, "null": {}, "default": {}, "primary": {}, "references": {}, "unique": {},
+	"check": {}, "constraint": {}, "generated": {}, "collate": {}, "auto_increment": {},
+}
+
+// tableConstraintWords start a table constraint instead of a column definition
+var tableConstraintWords = map[string]struct{}{
+	"constraint": {}, "primary": {}, "unique": {}, "check": {}, "foreign": {}, "exclude": {}, "like": {}, "key": {}, "index": {},
+}
+
+// parseSchema returns the tables of the sql files of a directory, the files are applied in name order
+// so migrations that alter or drop tables are taken into account, down migrations are skipped
+func parseSchema(dir, softDeleteColumn string) ([]tableSpec, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.sql"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	schema := newSchema()
+	for _, file := range files {
+		if strings.HasSuffix(file, ".down.sql") {
+			continue
+		}
+
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, statement := range splitStatements(stripComments(upMigration(string(content)))) {
+			if err := schema.apply(statement); err != nil {
+				return nil, fmt.Errorf("%s: %w", filepath.Base(file), err)
+			}
+		}
+	}
+
+	return schema.tables(softDeleteColumn), nil
+}
+
+type schema struct {
+	order  []string
+	byName map[string]*tableSpec
+}
+
+func newSchema() *schema {
+	return &schema{byName: map[string]*tableSpec{}}
+}
+
+func (s *schema) tables(softDeleteColumn string) []tableSpec {
+	tables := make([]tableSpec, 0, len(s.order))
+	for _, name := range s.order {
+		table := *s.byName[name]
+		if softDeleteColumn != "" && table.column(softDeleteColumn) >= 0 {
+			table.SoftDeleteColumn = softDeleteColumn
+		}
+
+		tables = append(tables, table)
+	}
+
+	return tables
+}
+
+func (s *schema) table(name string) (*tableSpec, error) {
+	table, ok := s.byName[name]
+	if !ok {
+		return nil, fmt.Errorf("table %s not found", name)
+	}
+
+	return table, nil
+}
+
+func (s *schema) apply(statement string) error {
+	if match := createTableRegexp.FindStringSubmatch(statement); match != nil {
+		return s.createTable(unquoteIdentifier(match[1]), match[2])
+	}
+
+	if match := alterTableRegexp.FindStringSubmatch(statement); match != nil {
+		table, err := s.table(unquoteIdentifier(match[1]))
+		if err != nil {
+			return err
+		}
+
+		for _, action := range splitTopLevel(match[2], ',') {
+			if err := s.alterTable(table, action); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	if match := dropTableRegexp.FindStringSubmatch(statement); match != nil {
+		for _, name := range splitTopLevel(match[1], ',') {
+			s.dropTable(unquoteIdentifier(name))
+		}
+	}
+
+	return nil
+}
+
+func (s *schema) createTable(name, body string) error {
+	if _, exists := s.byName[name]; exists {
+		return nil
+	}
+
+	table := &tableSpec{GoName: goIdentifier(lastPart(name)), Name: name}
+	for _, definition := range splitTopLevel(body, ',') {
+		if err := table.addDefinition(definition); err != nil {
+			return fmt.Errorf("table %s: %w", name, err)
+		}
+	}
+
+	s.order = append(s.order, name)
+	s.byName[name] = table
+
+	return nil
+}
+
+func (s *schema) dropTable(name string) {
+	if _, ok := s.byName[name]; !ok {
+		return
+	}
+
+	delete(s.byName, name)
+	for i, n := range s.order {
+		if n == name {
+			s.order = append(s.order[:i], s.order[i+1:]...)
+			break
+		}
+	}
+}
+
+func (s *schema) renameTable(table *tableSpec, name string) {
+	for i, n := range s.order {
+		if n == table.Name {
+			s.order[i] = name
+		}
+	}
+
+	delete(s.byName, table.Name)
+	table.Name = name
+	table.GoName = goIdentifier(lastPart(name))
+	s.byName[name] = table
+}
+
+func (s *schema) alterTable(table *tableSpec, action string) error {
+	words := strings.Fields(action)
+	if len(words) < 2 {
+		return nil
+	}
+
+	switch strings.ToLower(words[0]) {
+	case "add":
+		definition := strings.TrimSpace(action[len(words[0]):])
+		definition = trimKeywords(definition, "column")
+		definition = trimKeywords(definition, "if", "not", "exists")
+
+		return table.addDefinition(definition)
+	case "drop":
+		rest := strings.Fields(trimKeywords(trimKeywords(strings.TrimSpace(action[len(words[0]):]), "column"), "if", "exists"))
+		if len(rest) > 0 && !isKeyword(rest[0], "constraint") {
+			table.dropColumn(unquoteIdentifier(rest[0]))
+		}
+	case "rename":
+		rest := strings.Fields(trimKeywords(strings.TrimSpace(action[len(words[0]):]), "column"))
+		if len(rest) == 2 && isKeyword(rest[0], "to") {
+			s.renameTable(table, unquoteIdentifier(rest[1]))
+			return nil
+		}
+
+		if len(rest) == 3 && isKeyword(rest[1], "to") {
+			table.renameColumn(unquoteIdentifier(rest[0]), unquoteIdentifier(rest[2]))
+		}
+	case "alter":
+		rest := strings.Fields(trimKeywords(strings.TrimSpace(action[len(words[0]):]), "column"))
+		if len(rest) < 3 {
+			return nil
+		}
+
+		typeAt := 0
+		switch {
+		case isKeyword(rest[1], "type"):
+			typeAt = 2
+		case len(rest) > 4 && isKeyword(rest[1], "set") && isKeyword(rest[2], "data") && isKeyword(rest[3], "type"):
+			typeAt = 4
+		default:
+			return nil
+		}
+
+		if i := table.column(unquoteIdentifier(rest[0])); i >= 0 {
+			table.Columns[i].Type = columnTypeOfSQL(strings.Join(rest[typeAt:], " "))
+		}
+	}
+
+	return nil
+}
+
+// addDefinition adds a column definition or a primary key constraint
+func (t *tableSpec) addDefinition(definition string) error {
+	definition = strings.TrimSpace(definition)
+	words := strings.Fields(definition)
+	if len(words) == 0 {
+		return nil
+	}
+
+	if _, ok := tableConstraintWords[strings.ToLower(words[0])]; ok {
+		if match := primaryKeyRegexp.FindStringSubmatch(definition); match != nil {
+			for _, column := range splitTopLevel(match[1], ',') {
+				if i := t.column(unquoteIdentifier(column)); i >= 0 {
+					t.Columns[i].PrimaryKey = true
+				}
+			}
+		}
+
+		return nil
+	}
+
+	if len(words) < 2 {
+		return fmt.Errorf("column %s has no type", words[0])
+	}
+
+	name := unquoteIdentifier(words[0])
+	if t.column(name) >= 0 {
+		return nil
+	}
+
+	typeWords := []string{}
+	for _, word := range words[1:] {
+		if _, ok := columnConstraintWords[strings.ToLower(word)]; ok {
+			break
+		}
+
+		typeWords = append(typeWords, word)
+	}
+
+	t.Columns = append(t.Columns, columnSpec{
+		GoName:      goIdentifier(name),
+		Name:        name,
+		DomainField: domainField(name),
+		Type:        columnTypeOfSQL(strings.Join(typeWords, " ")),
+		PrimaryKey:  strings.Contains(strings.ToLower(definition), "primary key"),
+	})
+
+	return nil
+}
+
+func (t *tableSpec) column(name string) int {
+	for i, column := range t.Columns {
+		if column.Name == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (t *tableSpec) dropColumn(name string) {
+	if i := t.column(name); i >= 0 {
+		t.Columns = append(t.Columns[:i], t.Columns[i+1:]...)
+	}
+}
+
+func (t *tableSpec) renameColumn(from, to string) {
+	if i := t.column(from); i >= 0 {
+		t.Columns[i].Name = to
+		t.Columns[i].GoName = goIdentifier(to)
+		t.Columns[i].DomainField = domainField(to)
+	}
+}
+
+// columnTypeOfSQL returns the sqlcraft column type of a sql type, array and unknown types have no column type
+func columnTypeOfSQL(sqlType string) string {
+	sqlType = strings.ToLower(strings.TrimSpace(sqlType))
+	if strings.HasSuffix(sqlType, "]") {
+		return ""
+	}
+
+	if i := strings.IndexByte(sqlType, '('); i >= 0 {
+		sqlType = strings.TrimSpace(sqlType[:i])
+	}
+
+	switch {
+	case strings.HasPrefix(sqlType, "timestamp"), strings.HasPrefix(sqlType, "time"), sqlType == "date", sqlType == "datetime":
+		return "TimeColumnType"
+	case strings.HasPrefix(sqlType, "character"), strings.HasPrefix(sqlType, "varchar"):
+		return "TextColumnType"
+	case strings.HasPrefix(sqlType, "double"):
+		return "FloatColumnType"
+	}
+
+	return columnTypeBySQLType[sqlType]
+}
+
+var columnTypeBySQLType = map[string]string{
+	"text":        "TextColumnType",
+	"char":        "TextColumnType",
+	"citext":      "TextColumnType",
+	"smallint":    "IntColumnType",
+	"integer":     "IntColumnType",
+	"int":         "IntColumnType",
+	"bigint":      "IntColumnType",
+	"int2":        "IntColumnType",
+	"int4":        "IntColumnType",
+	"int8":        "IntColumnType",
+	"tinyint":     "IntColumnType",
+	"mediumint":   "IntColumnType",
+	"smallserial": "IntColumnType",
+	"serial":      "IntColumnType",
+	"bigserial":   "IntColumnType",
+	"real":        "FloatColumnType",
+	"float":       "FloatColumnType",
+	"float4":      "FloatColumnType",
+	"float8":      "FloatColumnType",
+	"numeric":     "DecimalColumnType",
+	"decimal":     "DecimalColumnType",
+	"money":       "DecimalColumnType",
+	"bool":        "BoolColumnType",
+	"boolean":     "BoolColumnType",
+	"uuid":        "UUIDColumnType",
+}
+
+// commonInitialisms are written in upper case in go identifiers
+var commonInitialisms = map[string]struct{}{
+	"api": {}, "html": {}, "http": {}, "id": {}, "ip": {}, "json": {}, "sql": {},
+	"uri": {}, "url": {}, "uuid": {}, "xml": {},
+}
+
+// goIdentifier converts a snake_case name into an exported go identifier, e.g. user_id into UserID
+func goIdentifier(name string) string {
+	builder := strings.Builder{}
+	for _, part := range strings.FieldsFunc(name, isNameSeparator) {
+		if _, ok := commonInitialisms[strings.ToLower(part)]; ok {
+			builder.WriteString(strings.ToUpper(part))
+			continue
+		}
+
+		builder.WriteString(capitalize(part))
+	}
+
+	return builder.String()
+}
+
+// domainField converts a snake_case column into a camelCase domain field, e.g. created_at into createdAt
+func domainField(column string) string {
+	parts := strings.FieldsFunc(column, isNameSeparator)
+	for i, part := range parts {
+		if i > 0 {
+			parts[i] = capitalize(part)
+		}
+	}
+
+	return strings.Join(parts, "")
+}
+
+func capitalize(s string) string {
+	runes := []rune(s)
+	if len(runes) == 0 {
+		return s
+	}
+
+	runes[0] = unicode.ToUpper(runes[0])
+
+	return string(runes)
+}
+
+func isNameSeparator(r rune) bool {
+	return r == '_' || r == '-' || r == ' '
+}
+
+func lastPart(name string) string {
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		return name[i+1:]
+	}
+
+	return name
+}
+
+// unquoteIdentifier removes the quotes of every part of an identifier, e.g. "public"."users" into public.users
+func unquoteIdentifier(identifier string) string {
+	return strings.NewReplacer(`"`, "", "`", "", "[", "", "]", "").Replace(strings.TrimSpace(identifier))
+}
+
+func isKeyword(word, keyword string) bool {
+	return strings.EqualFold(word, keyword)
+}
+
+// trimKeywords removes the given keywords from the start of s if all of them are present
+func trimKeywords(s string, keywords ...string) string {
+	words := strings.Fields(s)
+	if len(words) < len(keywords) {
+		return s
+	}
+
+	for i, keyword := range keywords {
+		if !isKeyword(words[i], keyword) {
+			return s
+		}
+	}
+
+	return strings.Join(words[len(keywords):], " ")
+}
+
+// downMarkers start the down migration of a file, as written by goose and dbmate
+var downMarkers = []string{"+goose down", "migrate:down"}
+
+// upMigration returns the sql before the down migration marker of a file
+func upMigration(sql string) string {
+	offset := 0
+	for _, line := range strings.SplitAfter(sql, "\n") {
+		comment, isComment := strings.CutPrefix(strings.TrimSpace(line), "--")
+		if isComment {
+			comment = strings.ToLower(strings.TrimSpace(comment))
+			for _, marker := range downMarkers {
+				if strings.HasPrefix(comment, marker) {
+					return sql[:offset]
+				}
+			}
+		}
+
+		offset += len(line)
+	}
+
+	return sql
+}
+
+// stripComments removes the line and block comments outside of quoted strings
+func stripComments(sql string) string {
+	builder := strings.Builder{}
+	var quote byte
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '-' && i+1 < len(sql) && sql[i+1] == '-':
+			for i < len(sql) && sql[i] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < len(sql) && sql[i+1] == '*':
+			end := strings.Index(sql[i+2:], "*/")
+			if end < 0 {
+				return builder.String()
+			}
+
+			i += end + 3
+			continue
+		}
+
+		if i < len(sql) {
+			builder.WriteByte(sql[i])
+		}
+	}
+
+	return builder.String()
+}
+
+func splitStatements(sql string) []string {
+	return splitTopLevel(sql, ';')
+}
+
+// splitTopLevel splits s by sep outside of parentheses and quoted strings, empty parts are dropped
+func splitTopLevel(s string, sep byte) []string {
+	var (
+		parts []string
+		depth int
+		quote byte
+		start int
+	)
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == sep && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	parts = append(parts, s[start:])
+
+	nonEmpty := parts[:0]
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			nonEmpty = append(nonEmpty, part)
+		}
+	}
+
+	return nonEmpty
+}
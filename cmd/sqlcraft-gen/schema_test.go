@@ -0,0 +1,125 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseSchema(t *testing.T) {
+	dir := t.TempDir()
+	migrations := map[string]string{
+		"001_users.sql": `
+-- users of the app
+CREATE TABLE IF NOT EXISTS "users" (
+	id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+	email varchar(255) NOT NULL UNIQUE, /* login */
+	balance numeric(10, 2) NOT NULL DEFAULT 0,
+	tags text[],
+	legacy_code int,
+	created_at timestamptz NOT NULL DEFAULT now(),
+	deleted_at timestamp with time zone
+);
+
+CREATE TABLE user_roles (
+	user_id uuid NOT NULL REFERENCES users (id),
+	role_name text NOT NULL DEFAULT 'guest; read only',
+	CONSTRAINT user_roles_pk PRIMARY KEY (user_id, role_name)
+);
+`,
+		"002_alter.sql": `
+-- +goose Up
+ALTER TABLE users ADD COLUMN is_active boolean NOT NULL DEFAULT true, DROP COLUMN legacy_code;
+ALTER TABLE users RENAME COLUMN email TO email_address;
+ALTER TABLE users ALTER COLUMN balance TYPE double precision;
+CREATE TABLE tmp (id int);
+DROP TABLE IF EXISTS tmp;
+
+-- +goose Down
+DROP TABLE users;
+`,
+		"003_index.sql": `
+-- migrate:up
+CREATE INDEX users_email_idx ON users (email_address);
+
+-- migrate:down
+ALTER TABLE users DROP COLUMN email_address;
+`,
+		"003_index.down.sql": `
+DROP TABLE user_roles;
+`,
+	}
+	for name, content := range migrations {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := parseSchema(dir, "deleted_at")
+	if err != nil {
+		t.Fatalf("parseSchema() error = %v", err)
+	}
+
+	want := []tableSpec{
+		{
+			GoName:           "Users",
+			Name:             "users",
+			SoftDeleteColumn: "deleted_at",
+			Columns: []columnSpec{
+				{GoName: "ID", Name: "id", DomainField: "id", Type: "UUIDColumnType", PrimaryKey: true},
+				{GoName: "EmailAddress", Name: "email_address", DomainField: "emailAddress", Type: "TextColumnType"},
+				{GoName: "Balance", Name: "balance", DomainField: "balance", Type: "FloatColumnType"},
+				{GoName: "Tags", Name: "tags", DomainField: "tags"},
+				{GoName: "CreatedAt", Name: "created_at", DomainField: "createdAt", Type: "TimeColumnType"},
+				{GoName: "DeletedAt", Name: "deleted_at", DomainField: "deletedAt", Type: "TimeColumnType"},
+				{GoName: "IsActive", Name: "is_active", DomainField: "isActive", Type: "BoolColumnType"},
+			},
+		},
+		{
+			GoName: "UserRoles",
+			Name:   "user_roles",
+			Columns: []columnSpec{
+				{GoName: "UserID", Name: "user_id", DomainField: "userId", Type: "UUIDColumnType", PrimaryKey: true},
+				{GoName: "RoleName", Name: "role_name", DomainField: "roleName", Type: "TextColumnType", PrimaryKey: true},
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseSchema() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseSchema_UnknownTable(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "001.sql"), []byte("ALTER TABLE users ADD COLUMN name text;"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := parseSchema(dir, ""); err == nil {
+		t.Errorf("parseSchema() expected an error for an unknown table")
+	}
+}
+
+func TestColumnTypeOfSQL(t *testing.T) {
+	tests := []struct {
+		sqlType string
+		want    string
+	}{
+		{sqlType: "BIGSERIAL", want: "IntColumnType"},
+		{sqlType: "character varying(20)", want: "TextColumnType"},
+		{sqlType: "numeric(10,2)", want: "DecimalColumnType"},
+		{sqlType: "double precision", want: "FloatColumnType"},
+		{sqlType: "timestamp without time zone", want: "TimeColumnType"},
+		{sqlType: "date", want: "TimeColumnType"},
+		{sqlType: "jsonb", want: ""},
+		{sqlType: "int[]", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sqlType, func(t *testing.T) {
+			if got := columnTypeOfSQL(tt.sqlType); got != tt.want {
+				t.Errorf("columnTypeOfSQL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
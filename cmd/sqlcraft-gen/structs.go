@@ -0,0 +1,271 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const tableDirective = "//sqlcraft:table"
+
+// parseStructs returns the package name and the tables of the structs with a table directive,
+// the generated file is skipped so it can be regenerated
+func parseStructs(dir, domainTag, generatedFile string) (string, []tableSpec, error) {
+	fileSet := token.NewFileSet()
+	filter := func(info os.FileInfo) bool {
+		return info.Name() != generatedFile && !strings.HasSuffix(info.Name(), "_test.go")
+	}
+
+	pkgs, err := parser.ParseDir(fileSet, dir, filter, parser.ParseComments)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if len(pkgs) != 1 {
+		return "", nil, fmt.Errorf("expected one package in %s, found %d", dir, len(pkgs))
+	}
+
+	var pkg *ast.Package
+	for _, p := range pkgs {
+		pkg = p
+	}
+
+	fileNames := make([]string, 0, len(pkg.Files))
+	for fileName := range pkg.Files {
+		fileNames = append(fileNames, fileName)
+	}
+	sort.Strings(fileNames)
+
+	structs := map[string]*ast.StructType{}
+	type directiveStruct struct {
+		name      string
+		directive string
+	}
+	var annotated []directiveStruct
+
+	for _, fileName := range fileNames {
+		for _, decl := range pkg.Files[fileName].Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+
+			for _, spec := range genDecl.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				structType, ok := typeSpec.Type.(*ast.StructType)
+				if !ok {
+					continue
+				}
+				structs[typeSpec.Name.Name] = structType
+
+				doc := typeSpec.Doc
+				if doc == nil && len(genDecl.Specs) == 1 {
+					doc = genDecl.Doc
+				}
+
+				if directive, ok := findDirective(doc); ok {
+					annotated = append(annotated, directiveStruct{name: typeSpec.Name.Name, directive: directive})
+				}
+			}
+		}
+	}
+
+	tables := make([]tableSpec, 0, len(annotated))
+	for _, s := range annotated {
+		table, err := parseDirective(s.name, s.directive)
+		if err != nil {
+			return "", nil, err
+		}
+
+		table.Columns = structColumns(structs, structs[s.name], domainTag, map[string]struct{}{})
+		if len(table.Columns) == 0 {
+			return "", nil, fmt.Errorf("struct %s has no db tagged fields", s.name)
+		}
+
+		tables = append(tables, table)
+	}
+
+	return pkg.Name, tables, nil
+}
+
+func findDirective(doc *ast.CommentGroup) (string, bool) {
+	if doc == nil {
+		return "", false
+	}
+
+	for _, comment := range doc.List {
+		if strings.HasPrefix(comment.Text, tableDirective+" ") {
+			return strings.TrimPrefix(comment.Text, tableDirective+" "), true
+		}
+	}
+
+	return "", false
+}
+
+// parseDirective parses "users softdelete=deleted_at"
+func parseDirective(goName, directive string) (tableSpec, error) {
+	fields := strings.Fields(directive)
+	if len(fields) == 0 {
+		return tableSpec{}, fmt.Errorf("struct %s: missing table name in %s directive", goName, tableDirective)
+	}
+
+	table := tableSpec{GoName: goName, Name: fields[0]}
+	for _, option := range fields[1:] {
+		key, value, _ := strings.Cut(option, "=")
+		switch key {
+		case "softdelete":
+			table.SoftDeleteColumn = value
+		default:
+			return tableSpec{}, fmt.Errorf("struct %s: unknown option %q", goName, key)
+		}
+	}
+
+	return table, nil
+}
+
+// structColumns returns the db tagged fields of a struct, embedded structs of the same package are flattened
+func structColumns(structs map[string]*ast.StructType, structType *ast.StructType, domainTag string, seen map[string]struct{}) []columnSpec {
+	var columns []columnSpec
+	for _, field := range structType.Fields.List {
+		tag := reflect.StructTag("")
+		if field.Tag != nil {
+			unquoted, err := strconv.Unquote(field.Tag.Value)
+			if err == nil {
+				tag = reflect.StructTag(unquoted)
+			}
+		}
+
+		dbTag, hasDBTag := tag.Lookup("db")
+		if len(field.Names) == 0 && !hasDBTag {
+			if embedded, ok := structs[typeName(field.Type)]; ok {
+				columns = append(columns, structColumns(structs, embedded, domainTag, seen)...)
+			}
+
+			continue
+		}
+
+		column, options, _ := strings.Cut(dbTag, ",")
+		if column == "" || column == "-" {
+			continue
+		}
+
+		if _, exists := seen[column]; exists {
+			continue
+		}
+		seen[column] = struct{}{}
+
+		goName := typeName(field.Type)
+		if len(field.Names) > 0 {
+			goName = field.Names[0].Name
+		}
+
+		if !ast.IsExported(goName) {
+			continue
+		}
+
+		domainField, _, _ := strings.Cut(tag.Get(domainTag), ",")
+		switch domainField {
+		case "":
+			domainField = goName
+		case "-":
+			// the column is hidden from the clients, it cannot be filtered or sorted by
+			domainField = ""
+		}
+
+		columns = append(columns, columnSpec{
+			GoName:      goName,
+			Name:        column,
+			DomainField: domainField,
+			Type:        columnTypeOfExpr(field.Type),
+			PrimaryKey:  hasOption(options, "pk"),
+		})
+	}
+
+	return columns
+}
+
+func hasOption(options, option string) bool {
+	for _, o := range strings.Split(options, ",") {
+		if o == option {
+			return true
+		}
+	}
+
+	return false
+}
+
+// typeName returns the name of a type expression without its pointer, package and type params
+func typeName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.StarExpr:
+		return typeName(t.X)
+	case *ast.SelectorExpr:
+		return t.Sel.Name
+	case *ast.IndexExpr:
+		return typeName(t.X)
+	default:
+		return ""
+	}
+}
+
+// columnTypeOfExpr returns the sqlcraft column type of a Go type expression,
+// pointers and sqlcraft.Optional are unwrapped
+func columnTypeOfExpr(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return columnTypeOfExpr(t.X)
+	case *ast.IndexExpr:
+		if typeName(t.X) == "Optional" {
+			return columnTypeOfExpr(t.Index)
+		}
+	case *ast.SelectorExpr:
+		return columnTypeByGoType[exprString(t)]
+	case *ast.Ident:
+		return columnTypeByGoType[t.Name]
+	}
+
+	return ""
+}
+
+func exprString(selector *ast.SelectorExpr) string {
+	pkg, ok := selector.X.(*ast.Ident)
+	if !ok {
+		return selector.Sel.Name
+	}
+
+	return pkg.Name + "." + selector.Sel.Name
+}
+
+var columnTypeByGoType = map[string]string{
+	"string":          "TextColumnType",
+	"int":             "IntColumnType",
+	"int8":            "IntColumnType",
+	"int16":           "IntColumnType",
+	"int32":           "IntColumnType",
+	"int64":           "IntColumnType",
+	"uint":            "IntColumnType",
+	"uint8":           "IntColumnType",
+	"uint16":          "IntColumnType",
+	"uint32":          "IntColumnType",
+	"uint64":          "IntColumnType",
+	"float32":         "FloatColumnType",
+	"float64":         "FloatColumnType",
+	"bool":            "BoolColumnType",
+	"time.Time":       "TimeColumnType",
+	"uuid.UUID":       "UUIDColumnType",
+	"decimal.Decimal": "DecimalColumnType",
+	"sql.NullString":  "TextColumnType",
+	"sql.NullInt64":   "IntColumnType",
+	"sql.NullInt32":   "IntColumnType",
+	"sql.NullFloat64": "FloatColumnType",
+	"sql.NullBool":    "BoolColumnType",
+	"sql.NullTime":    "TimeColumnType",
+}
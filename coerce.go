@@ -0,0 +1,116 @@
+package sqlcraft
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/techforge-lat/errortrace/v2"
+	"github.com/techforge-lat/errortrace/v2/errtype"
+)
+
+var ErrInvalidValue = errors.New("invalid value for column type")
+
+type ColumnType string
+
+const (
+	TextColumnType    ColumnType = "text"
+	IntColumnType     ColumnType = "int"
+	FloatColumnType   ColumnType = "float"
+	BoolColumnType    ColumnType = "bool"
+	TimeColumnType    ColumnType = "time"
+	UUIDColumnType    ColumnType = "uuid"
+	DecimalColumnType ColumnType = "decimal"
+)
+
+// ColumnTypes holds the type of the sql columns, by sql column name
+type ColumnTypes map[string]ColumnType
+
+// timeLayouts are the layouts accepted for time values, in order of precedence
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	time.DateOnly,
+}
+
+var decimalRegexp = regexp.MustCompile(`^[+-]?(\d+(\.\d*)?|\.\d+)([eE][+-]?\d+)?$`)
+
+// CoerceValue converts a string value into the go type of the given column type,
+// values that are not strings are returned as they are
+//
+// Decimal values are validated and returned as strings to keep their precision
+func CoerceValue(columnType ColumnType, value any) (any, error) {
+	str, ok := value.(string)
+	if !ok {
+		return value, nil
+	}
+
+	trimmed := strings.TrimSpace(str)
+
+	var (
+		coerced any
+		err     error
+	)
+
+	switch columnType {
+	case IntColumnType:
+		coerced, err = strconv.ParseInt(trimmed, 10, 64)
+	case FloatColumnType:
+		coerced, err = strconv.ParseFloat(trimmed, 64)
+	case BoolColumnType:
+		coerced, err = strconv.ParseBool(trimmed)
+	case TimeColumnType:
+		coerced, err = parseTime(trimmed)
+	case UUIDColumnType:
+		coerced, err = uuid.Parse(trimmed)
+	case DecimalColumnType:
+		coerced = trimmed
+		if !decimalRegexp.MatchString(trimmed) {
+			err = ErrInvalidValue
+		}
+	default:
+		return value, nil
+	}
+
+	if err != nil {
+		return nil, errortrace.
+			OnError(errors.Join(err, ErrInvalidValue)).
+			WithCode(errtype.UnprocessableEntity).
+			WithMessage(fmt.Sprintf("value %q is not a valid %s", str, columnType))
+	}
+
+	return coerced, nil
+}
+
+// CoerceValues converts every value of an IN list, the list can be a slice or a comma separated string
+func CoerceValues(columnType ColumnType, value any) ([]any, error) {
+	values := inValues(value)
+	for i, v := range values {
+		coerced, err := CoerceValue(columnType, v)
+		if err != nil {
+			return nil, err
+		}
+
+		values[i] = coerced
+	}
+
+	return values, nil
+}
+
+func parseTime(value string) (time.Time, error) {
+	var err error
+	for _, layout := range timeLayouts {
+		var t time.Time
+		t, err = time.Parse(layout, value)
+		if err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, err
+}
@@ -0,0 +1,98 @@
+package sqlcraft
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/techforge-lat/dafi/v2"
+)
+
+func TestCoerceValue(t *testing.T) {
+	tests := []struct {
+		name       string
+		columnType ColumnType
+		value      any
+		want       any
+		wantErr    bool
+	}{
+		{name: "int", columnType: IntColumnType, value: " 42", want: int64(42)},
+		{name: "invalid int", columnType: IntColumnType, value: "42a", wantErr: true},
+		{name: "float", columnType: FloatColumnType, value: "4.5", want: 4.5},
+		{name: "bool", columnType: BoolColumnType, value: "true", want: true},
+		{name: "invalid bool", columnType: BoolColumnType, value: "yes", wantErr: true},
+		{name: "date", columnType: TimeColumnType, value: "2024-05-01", want: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "rfc3339 time", columnType: TimeColumnType, value: "2024-05-01T10:30:00Z", want: time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)},
+		{name: "invalid time", columnType: TimeColumnType, value: "yesterday", wantErr: true},
+		{name: "uuid", columnType: UUIDColumnType, value: "6ba7b810-9dad-11d1-80b4-00c04fd430c8", want: uuid.MustParse("6ba7b810-9dad-11d1-80b4-00c04fd430c8")},
+		{name: "invalid uuid", columnType: UUIDColumnType, value: "6ba7b810", wantErr: true},
+		{name: "decimal", columnType: DecimalColumnType, value: "10.50", want: "10.50"},
+		{name: "invalid decimal", columnType: DecimalColumnType, value: "10,50", wantErr: true},
+		{name: "text", columnType: TextColumnType, value: " hernan ", want: " hernan "},
+		{name: "typed value is kept", columnType: IntColumnType, value: 42, want: 42},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CoerceValue(tt.columnType, tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CoerceValue() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CoerceValue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWhereWithOptions_ColumnTypes(t *testing.T) {
+	options := WhereOptions{
+		SQLColumnByDomainField: map[string]string{"id": "id", "active": "is_active", "name": "name"},
+		ColumnTypes:            ColumnTypes{"id": IntColumnType, "is_active": BoolColumnType},
+	}
+
+	tests := []struct {
+		name    string
+		filters dafi.Filters
+		want    Result
+		wantErr bool
+	}{
+		{
+			name:    "coerce by mapped column",
+			filters: dafi.Filters{{Field: "active", Value: "false"}, {Field: "name", Value: "hernan"}},
+			want: Result{
+				Sql:  " WHERE is_active = $1 AND name = $2",
+				Args: []any{false, "hernan"},
+			},
+		},
+		{
+			name:    "coerce in comma separated values",
+			filters: dafi.Filters{{Field: "id", Operator: dafi.In, Value: "1,2,3"}},
+			want: Result{
+				Sql:  " WHERE id IN ($1, $2, $3)",
+				Args: []any{int64(1), int64(2), int64(3)},
+			},
+		},
+		{
+			name:    "unparsable value",
+			filters: dafi.Filters{{Field: "id", Value: "one"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := WhereWithOptions(0, options, tt.filters...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("WhereWithOptions() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("WhereWithOptions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package sqlcraft
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/techforge-lat/errortrace/v2"
+	"github.com/techforge-lat/errortrace/v2/errtype"
+)
+
+var ErrInvalidColumnRef = errors.New("invalid column reference")
+
+var identifierRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)*$`)
+
+// ColumnRef marks a filter value as a column, so it is written as an identifier
+// instead of being bound as an argument, e.g. updated_at > created_at
+//
+// When the filters are mapped (WhereSafe, WhereWithOptions or the builders SQLColumnByDomainField)
+// the reference is a domain field and it is mapped like the filter field
+type ColumnRef string
+
+func (c ColumnRef) validate() error {
+	if identifierRegexp.MatchString(string(c)) {
+		return nil
+	}
+
+	return errortrace.
+		OnError(ErrInvalidColumnRef).
+		WithCode(errtype.UnprocessableEntity).
+		WithMessage(fmt.Sprintf("column %q is not a valid identifier", string(c)))
+}
+
+// refColumn maps a referenced domain field to its sql column
+func (o WhereOptions) refColumn(ref ColumnRef) (ColumnRef, error) {
+	field := string(ref)
+
+	if len(o.FieldPolicies) > 0 {
+		policy, err := o.FieldPolicies.policy(field)
+		if err != nil {
+			return "", err
+		}
+
+		if !policy.Filterable {
+			return "", errortrace.
+				OnError(ErrFieldNotFilterable).
+				WithCode(errtype.UnprocessableEntity).
+				WithMessage(fmt.Sprintf("field %q is not filterable", field))
+		}
+
+		return ColumnRef(policy.column(field)), nil
+	}
+
+	if len(o.SQLColumnByDomainField) == 0 {
+		return ref, nil
+	}
+
+	sqlColumnName, ok := o.SQLColumnByDomainField[field]
+	if !ok {
+		return "", errortrace.
+			OnError(ErrInvalidFieldName).
+			WithCode(errtype.UnprocessableEntity).
+			WithMessage(fmt.Sprintf("field %q not found", field))
+	}
+
+	return ColumnRef(sqlColumnName), nil
+}
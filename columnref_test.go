@@ -0,0 +1,91 @@
+package sqlcraft
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/techforge-lat/dafi/v2"
+)
+
+func TestWhereWithOptions_ColumnRef(t *testing.T) {
+	tests := []struct {
+		name    string
+		options WhereOptions
+		filters dafi.Filters
+		want    Result
+		wantErr bool
+	}{
+		{
+			name: "compare two columns",
+			filters: dafi.Filters{
+				{Field: "updated_at", Operator: dafi.Greater, Value: ColumnRef("created_at")},
+				{Field: "email", Value: "hernan_rm@outlook.es"},
+			},
+			want: Result{
+				Sql:  " WHERE updated_at > created_at AND email = $1",
+				Args: []any{"hernan_rm@outlook.es"},
+			},
+		},
+		{
+			name:    "maps the referenced domain field",
+			options: WhereOptions{SQLColumnByDomainField: map[string]string{"ownerID": "a.owner_id", "userID": "b.user_id"}},
+			filters: dafi.Filters{{Field: "ownerID", Value: ColumnRef("userID")}},
+			want: Result{
+				Sql:  " WHERE a.owner_id = b.user_id",
+				Args: []any{},
+			},
+		},
+		{
+			name:    "unknown referenced domain field",
+			options: WhereOptions{SQLColumnByDomainField: map[string]string{"ownerID": "a.owner_id"}},
+			filters: dafi.Filters{{Field: "ownerID", Value: ColumnRef("userID")}},
+			wantErr: true,
+		},
+		{
+			name:    "referenced field must be filterable",
+			options: WhereOptions{FieldPolicies: FieldPolicies{"ownerID": {Filterable: true}, "userID": {}}},
+			filters: dafi.Filters{{Field: "ownerID", Value: ColumnRef("userID")}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid identifier",
+			filters: dafi.Filters{{Field: "updated_at", Value: ColumnRef("created_at; DROP TABLE users")}},
+			wantErr: true,
+		},
+		{
+			name:    "in does not accept a column reference",
+			filters: dafi.Filters{{Field: "id", Operator: dafi.In, Value: ColumnRef("other_id")}},
+			wantErr: true,
+		},
+		{
+			name:    "in with a column type does not accept a column reference",
+			options: WhereOptions{ColumnTypes: ColumnTypes{"id": IntColumnType}},
+			filters: dafi.Filters{{Field: "id", Operator: dafi.In, Value: ColumnRef("other_id")}},
+			wantErr: true,
+		},
+		{
+			name:    "compare with a column type and a column reference",
+			options: WhereOptions{ColumnTypes: ColumnTypes{"id": IntColumnType}},
+			filters: dafi.Filters{{Field: "id", Value: ColumnRef("other_id")}},
+			want: Result{
+				Sql:  " WHERE id = other_id",
+				Args: []any{},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := WhereWithOptions(0, tt.options, tt.filters...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("WhereWithOptions() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("WhereWithOptions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package sqlcraft
 
 import (
+	"context"
+	"slices"
 	"strings"
 
 	"github.com/techforge-lat/dafi/v2"
@@ -11,9 +13,21 @@ type DeleteQuery struct {
 	returningColumns []string
 
 	rawValues []any
+	using     []Expr
+	dialect   Dialect
+
+	softDeleteColumn string
+
+	ctx     context.Context
+	tenancy tenancy
 
 	sqlColumnByDomainField map[string]string
+	fieldPolicies          FieldPolicies
+	columnTypes            ColumnTypes
+	emptyIn                EmptyInBehavior
+	emptyNotIn             EmptyInBehavior
 	filters                dafi.Filters
+	allowFullTable         bool
 }
 
 func DeleteFrom(table string) DeleteQuery {
@@ -35,6 +49,93 @@ func (d DeleteQuery) SQLColumnByDomainField(sqlColumnByDomainField map[string]st
 	return d
 }
 
+// FieldPolicies restricts the domain fields a client can filter by
+func (d DeleteQuery) FieldPolicies(fieldPolicies FieldPolicies) DeleteQuery {
+	d.fieldPolicies = fieldPolicies
+
+	return d
+}
+
+// ColumnTypes coerces the string filter values into the type of their sql column
+func (d DeleteQuery) ColumnTypes(columnTypes ColumnTypes) DeleteQuery {
+	d.columnTypes = columnTypes
+
+	return d
+}
+
+// OnEmptyIn defines how an IN filter with an empty list is built
+func (d DeleteQuery) OnEmptyIn(behavior EmptyInBehavior) DeleteQuery {
+	d.emptyIn = behavior
+
+	return d
+}
+
+// OnEmptyNotIn defines how a NOT IN filter with an empty list is built
+func (d DeleteQuery) OnEmptyNotIn(behavior EmptyInBehavior) DeleteQuery {
+	d.emptyNotIn = behavior
+
+	return d
+}
+
+// Using adds tables to delete with, their join conditions go in the WHERE filters,
+// e.g. DeleteFrom("t").Using("other").Where(dafi.Filter{Field: "t.id", Value: ColumnRef("other.t_id")})
+func (d DeleteQuery) Using(tables ...string) DeleteQuery {
+	d.using = append(slices.Clone(d.using), tablesToExprs(tables)...)
+
+	return d
+}
+
+// UsingSubquery adds a subquery to delete with, with the given alias
+func (d DeleteQuery) UsingSubquery(subquery Result, alias string) DeleteQuery {
+	d.using = append(slices.Clone(d.using), aliasedSubquery(subquery, alias))
+
+	return d
+}
+
+// SoftDelete turns the query into an UPDATE that sets the given column to now(),
+// with the same filters and returning columns, already deleted rows are left untouched
+func (d DeleteQuery) SoftDelete(column string) DeleteQuery {
+	d.softDeleteColumn = column
+
+	return d
+}
+
+// WithContext sets the context used to resolve the tenant
+func (d DeleteQuery) WithContext(ctx context.Context) DeleteQuery {
+	d.ctx = ctx
+
+	return d
+}
+
+// WithTenantScope scopes the query to the tenant from ForTenant or the query context
+func (d DeleteQuery) WithTenantScope(scope TenantScope) DeleteQuery {
+	d.tenancy.scope = &scope
+
+	return d
+}
+
+// ForTenant sets the tenant of a query built WithTenantScope, instead of taking it from the context
+func (d DeleteQuery) ForTenant(tenant any) DeleteQuery {
+	d.tenancy.tenant = tenant
+	d.tenancy.isSet = true
+
+	return d
+}
+
+// WithDialect sets the sql dialect, PostgreSQL by default
+func (d DeleteQuery) WithDialect(dialect Dialect) DeleteQuery {
+	d.dialect = dialect
+
+	return d
+}
+
+// AllowFullTable allows to build the query without filters, affecting every row of the table
+func (d DeleteQuery) AllowFullTable() DeleteQuery {
+	d.allowFullTable = true
+
+	return d
+}
+
 func (d DeleteQuery) Returning(columns ...string) DeleteQuery {
 	d.returningColumns = columns
 
@@ -42,29 +143,96 @@ func (d DeleteQuery) Returning(columns ...string) DeleteQuery {
 }
 
 func (d DeleteQuery) ToSQL() (Result, error) {
+	if d.dialect == MySQL && len(d.returningColumns) > 0 {
+		return Result{}, ErrReturningNotSupported
+	}
+
+	if d.softDeleteColumn != "" {
+		return d.softDeleteQuery().ToSQL()
+	}
+
 	builder := strings.Builder{}
 
-	builder.WriteString("DELETE FROM ")
-	builder.WriteString(d.table)
+	usingSQL, usingArgs := joinExprs(d.using, len(d.rawValues))
+	args := append([]any{}, usingArgs...)
 
-	args := []any{}
-	if len(d.filters) > 0 {
-		whereResult, err := WhereSafe(len(d.rawValues), d.sqlColumnByDomainField, d.filters...)
-		if err != nil {
-			return Result{}, err
+	if d.dialect == MySQL && len(usingSQL) > 0 {
+		builder.WriteString("DELETE ")
+		builder.WriteString(tableTarget(d.table))
+		builder.WriteString(" FROM ")
+		builder.WriteString(d.table)
+
+		for _, using := range usingSQL {
+			builder.WriteString(" JOIN ")
+			builder.WriteString(using)
 		}
-		args = whereResult.Args
+	} else {
+		builder.WriteString("DELETE FROM ")
+		builder.WriteString(d.table)
+	}
+
+	if d.dialect != MySQL && len(usingSQL) > 0 {
+		builder.WriteString(" USING ")
+		builder.WriteString(strings.Join(usingSQL, ", "))
+	}
+
+	whereResult, predicates, err := whereWithOptions(len(d.rawValues)+len(args), d.whereOptions(), d.filters...)
+	if err != nil {
+		return Result{}, err
+	}
 
-		builder.WriteString(whereResult.Sql)
+	if predicates == 0 && !d.allowFullTable {
+		return Result{}, ErrMissingFilters
 	}
 
+	scopes, err := d.tenancy.scopes(d.ctx, d.table)
+	if err != nil {
+		return Result{}, err
+	}
+	whereResult = andScopes(whereResult, len(d.rawValues)+len(args), scopes...)
+
+	args = append(args, whereResult.Args...)
+	builder.WriteString(whereResult.Sql)
+
 	if len(d.returningColumns) > 0 {
 		builder.WriteString(" RETURNING ")
 		builder.WriteString(strings.Join(d.returningColumns, ", "))
 	}
 
-	return Result{
+	return rebind(d.dialect, Result{
 		Sql:  builder.String(),
 		Args: args,
-	}, nil
+	}), nil
+}
+
+// softDeleteQuery returns the UPDATE used to soft-delete the rows
+func (d DeleteQuery) softDeleteQuery() UpdateQuery {
+	u := Update(d.table).
+		Set(d.softDeleteColumn, Raw("now()")).
+		SoftDelete(d.softDeleteColumn).
+		WithDialect(d.dialect).
+		Returning(d.returningColumns...).
+		Where(d.filters...)
+
+	u.sqlColumnByDomainField = d.sqlColumnByDomainField
+	u.fieldPolicies = d.fieldPolicies
+	u.columnTypes = d.columnTypes
+	u.emptyIn = d.emptyIn
+	u.emptyNotIn = d.emptyNotIn
+	u.allowFullTable = d.allowFullTable
+	u.from = d.using
+	u.ctx = d.ctx
+	u.tenancy = d.tenancy
+
+	return u
+}
+
+func (d DeleteQuery) whereOptions() WhereOptions {
+	return WhereOptions{
+		SQLColumnByDomainField: d.sqlColumnByDomainField,
+		FieldPolicies:          d.fieldPolicies,
+		ColumnTypes:            d.columnTypes,
+		EmptyIn:                d.emptyIn,
+		EmptyNotIn:             d.emptyNotIn,
+	}
 }
@@ -16,7 +16,7 @@ func TestDeleteQuery_ToSQL(t *testing.T) {
 	}{
 		{
 			name:  "simple delete",
-			query: DeleteFrom("users"),
+			query: DeleteFrom("users").AllowFullTable(),
 			want: Result{
 				Sql:  "DELETE FROM users",
 				Args: []any{},
@@ -25,7 +25,7 @@ func TestDeleteQuery_ToSQL(t *testing.T) {
 		},
 		{
 			name:  "delete with returning",
-			query: DeleteFrom("users").Returning("id"),
+			query: DeleteFrom("users").AllowFullTable().Returning("id"),
 			want: Result{
 				Sql:  "DELETE FROM users RETURNING id",
 				Args: []any{},
@@ -50,6 +50,66 @@ func TestDeleteQuery_ToSQL(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name:  "delete with match all filter chained by and",
+			query: DeleteFrom("users").Where(dafi.Filter{Field: "id", Value: 1}, dafi.Filter{Field: "role", Operator: dafi.NotIn, Value: []string{}}),
+			want: Result{
+				Sql:  "DELETE FROM users WHERE id = $1 AND 1 = 1",
+				Args: []any{1},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "error delete with match all filter chained by or",
+			query:   DeleteFrom("users").Where(dafi.Filter{Field: "id", Value: 1, ChainingKey: dafi.Or}, dafi.Filter{Field: "role", Operator: dafi.NotIn, Value: []string{}}),
+			want:    Result{},
+			wantErr: true,
+		},
+		{
+			name:    "error returning with mysql dialect",
+			query:   DeleteFrom("users").Where(dafi.Filter{Field: "id", Value: 1}).Returning("id").WithDialect(MySQL),
+			want:    Result{},
+			wantErr: true,
+		},
+		{
+			name:    "error delete without filters",
+			query:   DeleteFrom("users").Returning("id"),
+			want:    Result{},
+			wantErr: true,
+		},
+		{
+			name:    "error delete with skipped filters",
+			query:   DeleteFrom("users").Where(dafi.Filter{Field: "id", Operator: dafi.In, Value: []int{}}).OnEmptyIn(EmptyInMatchAll),
+			want:    Result{},
+			wantErr: true,
+		},
+		{
+			name:  "delete with empty in matches nothing",
+			query: DeleteFrom("users").Where(dafi.Filter{Field: "id", Operator: dafi.In, Value: []int{}}),
+			want: Result{
+				Sql:  "DELETE FROM users WHERE 1 = 0",
+				Args: []any{},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "delete using other table",
+			query: DeleteFrom("sessions").Using("users").Where(dafi.Filter{Field: "sessions.user_id", Value: ColumnRef("users.id")}, dafi.Filter{Field: "users.is_blocked", Value: true}),
+			want: Result{
+				Sql:  "DELETE FROM sessions USING users WHERE sessions.user_id = users.id AND users.is_blocked = $1",
+				Args: []any{true},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "delete using subquery with mysql dialect",
+			query: DeleteFrom("sessions s").UsingSubquery(Result{Sql: "SELECT id FROM users WHERE is_blocked = $1", Args: []any{true}}, "u").Where(dafi.Filter{Field: "s.user_id", Value: ColumnRef("u.id")}, dafi.Filter{Field: "s.device", Value: "web"}).WithDialect(MySQL),
+			want: Result{
+				Sql:  "DELETE s FROM sessions s JOIN (SELECT id FROM users WHERE is_blocked = ?) AS u WHERE s.user_id = u.id AND s.device = ?",
+				Args: []any{true, "web"},
+			},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
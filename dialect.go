@@ -0,0 +1,88 @@
+package sqlcraft
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrReturningNotSupported = errors.New("RETURNING is not supported by the dialect")
+
+type Dialect string
+
+const (
+	PostgreSQL Dialect = "postgres"
+	MySQL      Dialect = "mysql"
+)
+
+// rebind rewrites the $n placeholders of a result into the placeholders of the dialect,
+// for MySQL the args are reordered to follow the order of the ? placeholders
+func rebind(dialect Dialect, result Result) Result {
+	if dialect != MySQL {
+		return result
+	}
+
+	args := make([]any, 0, len(result.Args))
+	sql := replacePlaceholders(result.Sql, func(number int) string {
+		if number >= 1 && number <= len(result.Args) {
+			args = append(args, result.Args[number-1])
+		}
+
+		return "?"
+	})
+
+	return Result{
+		Sql:  sql,
+		Args: args,
+	}
+}
+
+// tablesToExprs returns the table names as expressions without args
+func tablesToExprs(tables []string) []Expr {
+	exprs := make([]Expr, 0, len(tables))
+	for _, table := range tables {
+		exprs = append(exprs, Raw(table))
+	}
+
+	return exprs
+}
+
+func aliasedSubquery(subquery Result, alias string) Expr {
+	expr := Subquery(subquery)
+	expr.Sql += " AS " + alias
+
+	return expr
+}
+
+// joinExprs renumbers the expressions placeholders after the given arg count
+// and returns their sql and args
+func joinExprs(exprs []Expr, argCount int) ([]string, []any) {
+	sqls := make([]string, 0, len(exprs))
+	args := []any{}
+	for _, expr := range exprs {
+		sqls = append(sqls, expr.rebase(argCount+len(args)))
+		args = append(args, expr.Args...)
+	}
+
+	return sqls, args
+}
+
+// tableTarget returns the name used to reference a table, its alias when it has one
+func tableTarget(table string) string {
+	fields := strings.Fields(table)
+	if len(fields) == 0 {
+		return table
+	}
+
+	return fields[len(fields)-1]
+}
+
+// qualifiedColumn prefixes a column with the name used to reference its table,
+// so the column is not ambiguous when other tables are joined
+func qualifiedColumn(table, column string) string {
+	target := tableTarget(table)
+	if column == "" || target == "" || strings.Contains(column, ".") {
+		return column
+	}
+
+	return target + "." + column
+}
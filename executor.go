@@ -0,0 +1,151 @@
+package sqlcraft
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/techforge-lat/errortrace/v2"
+	"github.com/techforge-lat/errortrace/v2/errtype"
+)
+
+// DB executes sql statements, it is implemented by *sql.DB, *sql.Tx and *sql.Conn
+type DB interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// Statement is anything that builds a sql statement, every query builder and Result are statements
+type Statement interface {
+	ToSQL() (Result, error)
+}
+
+// ToSQL returns the result itself so an already built Result can be executed as a Statement
+func (r Result) ToSQL() (Result, error) {
+	return r, nil
+}
+
+// Exec builds and executes a statement that returns no rows
+func Exec(ctx context.Context, db DB, statement Statement) (sql.Result, error) {
+	var sqlResult sql.Result
+	err := Execute(ctx, ExecOperation, statement, func(ctx context.Context, result Result) (int64, error) {
+		var err error
+		sqlResult, err = db.ExecContext(ctx, result.Sql, result.Args...)
+		if err != nil {
+			return -1, WrapDBError(err)
+		}
+
+		rowsAffected, err := sqlResult.RowsAffected()
+		if err != nil {
+			return -1, nil
+		}
+
+		return rowsAffected, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return sqlResult, nil
+}
+
+// Query builds and executes a statement that returns rows, the caller must close them
+func Query(ctx context.Context, db DB, statement Statement) (*sql.Rows, error) {
+	var rows *sql.Rows
+	err := Execute(ctx, QueryOperation, statement, func(ctx context.Context, result Result) (int64, error) {
+		var err error
+		rows, err = db.QueryContext(ctx, result.Sql, result.Args...)
+
+		return -1, WrapDBError(err)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// QueryRow builds and executes a statement that returns at most one row and scans it into dest,
+// if there are no rows it returns a not found error
+func QueryRow(ctx context.Context, db DB, statement Statement, dest ...any) error {
+	return Execute(ctx, QueryRowOperation, statement, func(ctx context.Context, result Result) (int64, error) {
+		err := db.QueryRowContext(ctx, result.Sql, result.Args...).Scan(dest...)
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, WrapDBError(err)
+		}
+
+		if err != nil {
+			return -1, WrapDBError(err)
+		}
+
+		return 1, nil
+	})
+}
+
+// sqlStateError is implemented by the errors of the postgres drivers (pgconn.PgError, pq.Error)
+type sqlStateError interface {
+	SQLState() string
+}
+
+// SQLState returns the SQLSTATE code of a driver error, or an empty string if it has none
+func SQLState(err error) string {
+	var stateErr sqlStateError
+	if errors.As(err, &stateErr) {
+		return stateErr.SQLState()
+	}
+
+	var errTrace *errortrace.Error
+	if errors.As(err, &errTrace) && errTrace.Cause != nil && errTrace.Cause != err {
+		return SQLState(errTrace.Cause)
+	}
+
+	return ""
+}
+
+// WrapDBError wraps a driver error into an errortrace error with a code that matches its cause,
+// the original error is kept as the cause so errortrace.Is(err, sql.ErrNoRows) still works
+func WrapDBError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var errTrace *errortrace.Error
+	if errors.As(err, &errTrace) {
+		return err
+	}
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return errortrace.OnError(err).WithCode(errtype.NotFound).WithMessage("record not found")
+	}
+
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return errortrace.OnError(err).WithCode(errtype.InternalError).WithMessage("the query was canceled")
+	}
+
+	code, message := codeBySQLState(SQLState(err))
+
+	return errortrace.OnError(err).WithCode(code).WithMessage(message)
+}
+
+func codeBySQLState(state string) (errtype.Code, string) {
+	switch {
+	case state == "23505":
+		return CodeConflict, "the record already exists"
+	case state == "23503":
+		return errtype.UnprocessableEntity, "the record references a record that does not exist or is still referenced"
+	case state == "23502", state == "23514":
+		return errtype.UnprocessableEntity, "the record does not satisfy a constraint"
+	case state == "40001", state == "40P01":
+		return CodeConflict, "the transaction conflicted with a concurrent one, try again"
+	case state == "42501":
+		return errtype.Forbidden, "insufficient privileges"
+	case strings.HasPrefix(state, "22"):
+		return errtype.BadRequest, "invalid data"
+	case strings.HasPrefix(state, "23"):
+		return errtype.UnprocessableEntity, "integrity constraint violation"
+	default:
+		return errtype.InternalError, "database error"
+	}
+}
@@ -0,0 +1,308 @@
+package sqlcraft
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/techforge-lat/dafi/v2"
+	"github.com/techforge-lat/errortrace/v2"
+	"github.com/techforge-lat/errortrace/v2/errtype"
+)
+
+// fakeDatabase is a database/sql driver that records the executed statements
+// and answers every query with the same rows
+type fakeDatabase struct {
+	mu         sync.Mutex
+	statements []Result
+	columns    []string
+	rows       [][]driver.Value
+	// errs are returned by the next statements, in order
+	errs []error
+}
+
+func newFakeDB(t *testing.T, database *fakeDatabase) *sql.DB {
+	db := sql.OpenDB(fakeConnector{database: database})
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
+
+func (f *fakeDatabase) record(query string, args []driver.NamedValue) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	values := make([]any, 0, len(args))
+	for _, arg := range args {
+		values = append(values, arg.Value)
+	}
+	if len(values) == 0 {
+		values = nil
+	}
+	f.statements = append(f.statements, Result{Sql: query, Args: values})
+
+	if len(f.errs) == 0 {
+		return nil
+	}
+
+	err := f.errs[0]
+	f.errs = f.errs[1:]
+
+	return err
+}
+
+func (f *fakeDatabase) executed() []Result {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]Result{}, f.statements...)
+}
+
+type fakeConnector struct {
+	database *fakeDatabase
+}
+
+func (c fakeConnector) Connect(context.Context) (driver.Conn, error) {
+	return fakeConn{database: c.database}, nil
+}
+
+func (c fakeConnector) Driver() driver.Driver {
+	return fakeDriver{}
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) {
+	return nil, errors.New("use fakeConnector")
+}
+
+type fakeConn struct {
+	database *fakeDatabase
+}
+
+func (c fakeConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepare not supported")
+}
+
+func (c fakeConn) Close() error {
+	return nil
+}
+
+func (c fakeConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c fakeConn) BeginTx(context.Context, driver.TxOptions) (driver.Tx, error) {
+	if err := c.database.record("BEGIN", nil); err != nil {
+		return nil, err
+	}
+
+	return fakeTx{database: c.database}, nil
+}
+
+func (c fakeConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if err := c.database.record(query, args); err != nil {
+		return nil, err
+	}
+
+	return driver.RowsAffected(1), nil
+}
+
+func (c fakeConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if err := c.database.record(query, args); err != nil {
+		return nil, err
+	}
+
+	c.database.mu.Lock()
+	defer c.database.mu.Unlock()
+
+	return &fakeRows{columns: c.database.columns, rows: c.database.rows}, nil
+}
+
+type fakeTx struct {
+	database *fakeDatabase
+}
+
+func (t fakeTx) Commit() error {
+	return t.database.record("COMMIT", nil)
+}
+
+func (t fakeTx) Rollback() error {
+	return t.database.record("ROLLBACK", nil)
+}
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+	next    int
+}
+
+func (r *fakeRows) Columns() []string {
+	return r.columns
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.next >= len(r.rows) {
+		return io.EOF
+	}
+
+	copy(dest, r.rows[r.next])
+	r.next++
+
+	return nil
+}
+
+// fakePgError is a driver error with a SQLSTATE code
+type fakePgError struct {
+	code string
+}
+
+func (e fakePgError) Error() string {
+	return "pg error " + e.code
+}
+
+func (e fakePgError) SQLState() string {
+	return e.code
+}
+
+func TestExec(t *testing.T) {
+	database := &fakeDatabase{}
+	db := newFakeDB(t, database)
+
+	query := Update("users").Set("name", "hernan").Where(dafi.Filter{Field: "id", Value: 1})
+	if _, err := Exec(context.Background(), db, query); err != nil {
+		t.Fatalf("Exec() error = %v", err)
+	}
+
+	want := []Result{{Sql: "UPDATE users SET name = $1 WHERE id = $2", Args: []any{"hernan", int64(1)}}}
+	if got := database.executed(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Exec() executed = %v, want %v", got, want)
+	}
+}
+
+func TestExec_BuildError(t *testing.T) {
+	database := &fakeDatabase{}
+	db := newFakeDB(t, database)
+
+	_, err := Exec(context.Background(), db, DeleteFrom("users"))
+	if !errors.Is(err, ErrMissingFilters) {
+		t.Errorf("Exec() error = %v, want %v", err, ErrMissingFilters)
+	}
+
+	if got := database.executed(); len(got) != 0 {
+		t.Errorf("Exec() executed = %v, want nothing", got)
+	}
+}
+
+func TestQuery(t *testing.T) {
+	database := &fakeDatabase{
+		columns: []string{"id", "name"},
+		rows:    [][]driver.Value{{int64(1), "hernan"}, {int64(2), "maria"}},
+	}
+	db := newFakeDB(t, database)
+
+	rows, err := Query(context.Background(), db, Result{Sql: "SELECT id, name FROM users"})
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var (
+			id   int64
+			name string
+		)
+		if err := rows.Scan(&id, &name); err != nil {
+			t.Fatalf("Scan() error = %v", err)
+		}
+		names = append(names, name)
+	}
+
+	if want := []string{"hernan", "maria"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Query() names = %v, want %v", names, want)
+	}
+}
+
+func TestQueryRow(t *testing.T) {
+	t.Run("scans the row", func(t *testing.T) {
+		db := newFakeDB(t, &fakeDatabase{columns: []string{"name"}, rows: [][]driver.Value{{"hernan"}}})
+
+		var name string
+		if err := QueryRow(context.Background(), db, Select("name").From("users"), &name); err != nil {
+			t.Fatalf("QueryRow() error = %v", err)
+		}
+
+		if name != "hernan" {
+			t.Errorf("QueryRow() name = %v, want hernan", name)
+		}
+	})
+
+	t.Run("no rows is not found", func(t *testing.T) {
+		db := newFakeDB(t, &fakeDatabase{columns: []string{"name"}})
+
+		var name string
+		err := QueryRow(context.Background(), db, Select("name").From("users"), &name)
+		if !errortrace.Is(err, sql.ErrNoRows) {
+			t.Fatalf("QueryRow() error = %v, want %v", err, sql.ErrNoRows)
+		}
+
+		var errTrace *errortrace.Error
+		if !errors.As(err, &errTrace) || errTrace.Code != string(errtype.NotFound) {
+			t.Errorf("QueryRow() error code = %v, want %v", err, errtype.NotFound)
+		}
+	})
+}
+
+func TestWrapDBError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want errtype.Code
+	}{
+		{name: "unique violation", err: fakePgError{code: "23505"}, want: CodeConflict},
+		{name: "foreign key violation", err: fakePgError{code: "23503"}, want: errtype.UnprocessableEntity},
+		{name: "serialization failure", err: fakePgError{code: "40001"}, want: CodeConflict},
+		{name: "invalid text representation", err: fakePgError{code: "22P02"}, want: errtype.BadRequest},
+		{name: "insufficient privilege", err: fakePgError{code: "42501"}, want: errtype.Forbidden},
+		{name: "no rows", err: sql.ErrNoRows, want: errtype.NotFound},
+		{name: "unknown error", err: errors.New("connection reset"), want: errtype.InternalError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := WrapDBError(tt.err)
+
+			var errTrace *errortrace.Error
+			if !errors.As(err, &errTrace) {
+				t.Fatalf("WrapDBError() = %v, want an errortrace error", err)
+			}
+
+			if errTrace.Code != string(tt.want) {
+				t.Errorf("WrapDBError() code = %v, want %v", errTrace.Code, tt.want)
+			}
+
+			if !errortrace.Is(err, tt.err) {
+				t.Errorf("WrapDBError() cause = %v, want %v", errTrace.Cause, tt.err)
+			}
+		})
+	}
+}
+
+func TestSQLState(t *testing.T) {
+	if got := SQLState(WrapDBError(fakePgError{code: "23505"})); got != "23505" {
+		t.Errorf("SQLState() = %v, want 23505", got)
+	}
+
+	if got := SQLState(errors.New("connection reset")); got != "" {
+		t.Errorf("SQLState() = %v, want empty", got)
+	}
+}
@@ -0,0 +1,80 @@
+package sqlcraft
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Expr is a raw sql expression with its own args, its placeholders are numbered from $1
+// and they are renumbered when the expression is written in a query
+type Expr struct {
+	Sql  string
+	Args []any
+}
+
+// Default sets a column to its default value
+var Default = Raw("DEFAULT")
+
+// Raw returns an expression, e.g. Raw("views + $1", 1) or Raw("now()")
+func Raw(sql string, args ...any) Expr {
+	return Expr{
+		Sql:  sql,
+		Args: args,
+	}
+}
+
+// Subquery returns the result of a query as a parenthesized expression
+func Subquery(query Result) Expr {
+	return Expr{
+		Sql:  "(" + query.Sql + ")",
+		Args: query.Args,
+	}
+}
+
+// rebase returns the expression sql with its placeholders shifted by the given offset,
+// placeholders inside quoted strings are kept as they are
+func (e Expr) rebase(offset int) string {
+	return shiftPlaceholders(e.Sql, offset)
+}
+
+func shiftPlaceholders(sql string, offset int) string {
+	if offset == 0 {
+		return sql
+	}
+
+	return replacePlaceholders(sql, func(number int) string {
+		return "$" + strconv.Itoa(number+offset)
+	})
+}
+
+// replacePlaceholders replaces every $n placeholder outside quoted strings with the result of replace
+func replacePlaceholders(sql string, replace func(number int) string) string {
+	if !strings.Contains(sql, "$") {
+		return sql
+	}
+
+	builder := strings.Builder{}
+	inQuote := false
+	for i := 0; i < len(sql); i++ {
+		char := sql[i]
+		if char == '\'' {
+			inQuote = !inQuote
+		}
+
+		end := i + 1
+		for end < len(sql) && sql[end] >= '0' && sql[end] <= '9' {
+			end++
+		}
+
+		if char != '$' || inQuote || end == i+1 {
+			builder.WriteByte(char)
+			continue
+		}
+
+		number, _ := strconv.Atoi(sql[i+1 : end])
+		builder.WriteString(replace(number))
+		i = end - 1
+	}
+
+	return builder.String()
+}
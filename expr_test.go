@@ -0,0 +1,25 @@
+package sqlcraft
+
+import "testing"
+
+func TestExpr_rebase(t *testing.T) {
+	tests := []struct {
+		name   string
+		expr   Expr
+		offset int
+		want   string
+	}{
+		{name: "no placeholders", expr: Raw("now()"), offset: 3, want: "now()"},
+		{name: "one placeholder", expr: Raw("views + $1", 1), offset: 2, want: "views + $3"},
+		{name: "many placeholders", expr: Raw("greatest($1, $2) + $10"), offset: 5, want: "greatest($6, $7) + $15"},
+		{name: "quoted placeholder", expr: Raw("concat('$1', $1)"), offset: 1, want: "concat('$1', $2)"},
+		{name: "dollar without number", expr: Raw("price::money || '$'"), offset: 1, want: "price::money || '$'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.expr.rebase(tt.offset); got != tt.want {
+				t.Errorf("Expr.rebase() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
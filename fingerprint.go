@@ -0,0 +1,111 @@
+package sqlcraft
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+)
+
+// Fingerprint identifies the shape of a statement regardless of its values
+type Fingerprint struct {
+	// Shape is the normalized sql, e.g. SELECT id FROM users WHERE id IN (...) AND name = ?
+	Shape string
+	// Hash is a short hash of the shape, suitable as a metric label only:
+	// statements that differ in their literals or in the length of their lists share it
+	Hash string
+	// Key is a hash of the exact sql, suitable as a cache key of the statement, e.g. of a prepared statement,
+	// the args are not part of it
+	Key string
+}
+
+var (
+	placeholderListRegexp = regexp.MustCompile(`\(\?(?:, \?)*\)`)
+	repeatedListRegexp    = regexp.MustCompile(`\(\.\.\.\)(?:, \(\.\.\.\))+`)
+	spaceAfterRegexp      = regexp.MustCompile(`([(\[]) `)
+	spaceBeforeRegexp     = regexp.MustCompile(` ([)\],])`)
+	commaRegexp           = regexp.MustCompile(`,(\S)`)
+)
+
+// Fingerprint returns the shape of the statement and its hash, placeholders and literal values
+// become ?, whitespace is collapsed, placeholder lists like IN ($1, $2) become (...) and the
+// rows of a multi-row INSERT collapse into one, so the same query has the same fingerprint
+// whatever its values, the number of values of an IN or the number of inserted rows,
+// while its Key tells apart every distinct sql
+func (r Result) Fingerprint() Fingerprint {
+	shape := normalizeSQL(r.Sql)
+	sum := sha256.Sum256([]byte(shape))
+	key := sha256.Sum256([]byte(r.Sql))
+
+	return Fingerprint{
+		Shape: shape,
+		Hash:  hex.EncodeToString(sum[:8]),
+		Key:   hex.EncodeToString(key[:]),
+	}
+}
+
+func normalizeSQL(sql string) string {
+	builder := strings.Builder{}
+	lastSpace := true
+	for i := 0; i < len(sql); i++ {
+		char := sql[i]
+		switch {
+		case char == ' ' || char == '\t' || char == '\n' || char == '\r':
+			if !lastSpace {
+				builder.WriteByte(' ')
+			}
+			lastSpace = true
+
+			continue
+		case char == '\'':
+			for i++; i < len(sql); i++ {
+				if sql[i] == '\'' {
+					if i+1 < len(sql) && sql[i+1] == '\'' {
+						i++
+						continue
+					}
+
+					break
+				}
+			}
+			builder.WriteByte('?')
+		case char == '"' || char == '`':
+			end := strings.IndexByte(sql[i+1:], char)
+			if end < 0 {
+				end = len(sql) - i - 1
+			}
+
+			builder.WriteString(sql[i:min(i+end+2, len(sql))])
+			i += end + 1
+		case char == '$' && i+1 < len(sql) && isDigit(sql[i+1]):
+			for i+1 < len(sql) && isDigit(sql[i+1]) {
+				i++
+			}
+			builder.WriteByte('?')
+		case isDigit(char) && (i == 0 || !isIdentifierChar(sql[i-1]) && sql[i-1] != '.'):
+			for i+1 < len(sql) && (isDigit(sql[i+1]) || sql[i+1] == '.') {
+				i++
+			}
+			builder.WriteByte('?')
+		case isIdentifierChar(char):
+			end := i
+			for end < len(sql) && isIdentifierChar(sql[end]) {
+				end++
+			}
+			builder.WriteString(sql[i:end])
+			i = end - 1
+		default:
+			builder.WriteByte(char)
+		}
+
+		lastSpace = false
+	}
+
+	shape := strings.TrimSpace(builder.String())
+	shape = commaRegexp.ReplaceAllString(shape, ", $1")
+	shape = spaceAfterRegexp.ReplaceAllString(shape, "$1")
+	shape = spaceBeforeRegexp.ReplaceAllString(shape, "$1")
+	shape = placeholderListRegexp.ReplaceAllString(shape, "(...)")
+
+	return repeatedListRegexp.ReplaceAllString(shape, "(...)")
+}
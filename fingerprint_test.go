@@ -0,0 +1,97 @@
+package sqlcraft
+
+import (
+	"testing"
+
+	"github.com/techforge-lat/dafi/v2"
+)
+
+func TestResult_Fingerprint(t *testing.T) {
+	tests := []struct {
+		name string
+		sql  string
+		want string
+	}{
+		{
+			name: "placeholders and literals",
+			sql:  "SELECT  id,name FROM users\n\tWHERE id = $1 AND status = 'active' AND age > 18 AND score < 4.5 AND t1.x = ?",
+			want: "SELECT id, name FROM users WHERE id = ? AND status = ? AND age > ? AND score < ? AND t1.x = ?",
+		},
+		{
+			name: "in list",
+			sql:  "SELECT id FROM users WHERE id IN ( $1, $2,$3 ) AND role NOT IN ($4)",
+			want: "SELECT id FROM users WHERE id IN (...) AND role NOT IN (...)",
+		},
+		{
+			name: "multi-row insert",
+			sql:  "INSERT INTO users (name, email) VALUES ($1, $2), ($3, $4), ($5, $6) RETURNING id",
+			want: "INSERT INTO users (name, email) VALUES (...) RETURNING id",
+		},
+		{
+			name: "quoted strings and identifiers",
+			sql:  `SELECT "Order Id", 'it''s $1' FROM "my table" WHERE note = 'a, b'`,
+			want: `SELECT "Order Id", ? FROM "my table" WHERE note = ?`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Result{Sql: tt.sql}.Fingerprint()
+			if got.Shape != tt.want {
+				t.Errorf("Fingerprint().Shape = %v, want %v", got.Shape, tt.want)
+			}
+
+			if len(got.Hash) != 16 {
+				t.Errorf("Fingerprint().Hash = %v, want 16 hex chars", got.Hash)
+			}
+		})
+	}
+}
+
+func TestResult_Fingerprint_Stable(t *testing.T) {
+	build := func(ids ...any) Fingerprint {
+		result, err := Select("id").From("users").Where(dafi.Filter{Field: "id", Operator: dafi.In, Value: ids}).ToSQL()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return result.Fingerprint()
+	}
+
+	one, three := build(1), build(1, 2, 3)
+	if one.Shape != three.Shape || one.Hash != three.Hash {
+		t.Errorf("Fingerprint() = %v and %v, want the same shape and hash", one, three)
+	}
+
+	if one.Key == three.Key {
+		t.Errorf("Fingerprint() expected different keys for different in lists")
+	}
+
+	other, _ := Select("id").From("accounts").Where(dafi.Filter{Field: "id", Value: 1}).ToSQL()
+	if other.Fingerprint().Hash == build(1).Hash {
+		t.Errorf("Fingerprint() expected different hashes for different shapes")
+	}
+}
+
+func TestResult_Fingerprint_Key(t *testing.T) {
+	page := func(page uint) Fingerprint {
+		result, err := Select("id").From("users").Limit(10).Page(page).ToSQL()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return result.Fingerprint()
+	}
+
+	second, third := page(2), page(3)
+	if second.Hash != third.Hash {
+		t.Errorf("Fingerprint().Hash = %v and %v, want the same hash", second.Hash, third.Hash)
+	}
+
+	if second.Key == third.Key {
+		t.Errorf("Fingerprint().Key = %v for both pages, want different keys", second.Key)
+	}
+
+	if again := page(2); again.Key != second.Key {
+		t.Errorf("Fingerprint().Key = %v and %v, want the same key", again.Key, second.Key)
+	}
+}
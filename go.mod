@@ -6,9 +6,17 @@ toolchain go1.24.0
 
 require (
 	github.com/google/uuid v1.6.0
+	github.com/jackc/pgx/v5 v5.7.4
 	github.com/techforge-lat/errortrace v0.5.1
 )
 
+require (
+	github.com/jackc/pgpassfile v1.0.0 // indirect
+	github.com/jackc/pgservicefile v0.0.0-20240606120523-5a60cdf6a761 // indirect
+	golang.org/x/crypto v0.31.0 // indirect
+	golang.org/x/text v0.21.0 // indirect
+)
+
 require (
 	github.com/techforge-lat/dafi v1.1.0 // indirect
 	github.com/techforge-lat/dafi/v2 v2.6.0 // indirect
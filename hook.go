@@ -0,0 +1,199 @@
+package sqlcraft
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// Operation is the kind of execution of a statement
+type Operation string
+
+const (
+	ExecOperation     Operation = "exec"
+	QueryOperation    Operation = "query"
+	QueryRowOperation Operation = "query_row"
+	CopyOperation     Operation = "copy"
+)
+
+// BuildEvent describes the build of a statement
+type BuildEvent struct {
+	Statement Statement
+	Result    Result
+	Duration  time.Duration
+	Err       error
+}
+
+// QueryEvent describes the execution of a built statement
+type QueryEvent struct {
+	Operation   Operation
+	Result      Result
+	Fingerprint Fingerprint
+	Start       time.Time
+	Duration    time.Duration
+	// RowsAffected are the rows affected by an exec or scanned by a query, -1 when unknown
+	RowsAffected int64
+	Err          error
+}
+
+// Hook observes the statements built and executed by the executor helpers,
+// the Before methods return the context used by the rest of the operation
+// so a hook can start a tracing span and end it in the After method
+type Hook interface {
+	BeforeBuild(ctx context.Context, statement Statement) context.Context
+	AfterBuild(ctx context.Context, event BuildEvent)
+	BeforeQuery(ctx context.Context, event QueryEvent) context.Context
+	AfterQuery(ctx context.Context, event QueryEvent)
+}
+
+// NopHook implements every Hook method doing nothing, embed it to implement only some of them
+type NopHook struct{}
+
+func (NopHook) BeforeBuild(ctx context.Context, _ Statement) context.Context  { return ctx }
+func (NopHook) AfterBuild(context.Context, BuildEvent)                        {}
+func (NopHook) BeforeQuery(ctx context.Context, _ QueryEvent) context.Context { return ctx }
+func (NopHook) AfterQuery(context.Context, QueryEvent)                        {}
+
+type hooksKey struct{}
+
+// WithHooks returns a context that runs the given hooks, after the ones already in ctx,
+// on every statement executed with it
+func WithHooks(ctx context.Context, hooks ...Hook) context.Context {
+	current := hooksFrom(ctx)
+
+	return context.WithValue(ctx, hooksKey{}, append(current[:len(current):len(current)], hooks...))
+}
+
+func hooksFrom(ctx context.Context) []Hook {
+	hooks, _ := ctx.Value(hooksKey{}).([]Hook)
+
+	return hooks
+}
+
+// Execute builds a statement and runs it with the hooks of the context, run returns the rows affected
+// or scanned, -1 when unknown. It is used by the executor helpers and lets other drivers share the hooks
+func Execute(ctx context.Context, operation Operation, statement Statement, run func(ctx context.Context, result Result) (int64, error)) error {
+	result, err := Build(ctx, statement)
+	if err != nil {
+		return err
+	}
+
+	return Run(ctx, operation, result, run)
+}
+
+// Build builds a statement with the build hooks of the context, for drivers that run the result later,
+// like the statements of a batch, with Run
+func Build(ctx context.Context, statement Statement) (Result, error) {
+	return build(ctx, hooksFrom(ctx), statement)
+}
+
+// Run runs a built statement with the query hooks of the context, see Execute
+func Run(ctx context.Context, operation Operation, result Result, run func(ctx context.Context, result Result) (int64, error)) error {
+	hooks := hooksFrom(ctx)
+
+	event := QueryEvent{Operation: operation, Result: result, Start: time.Now(), RowsAffected: -1}
+	if len(hooks) > 0 {
+		event.Fingerprint = result.Fingerprint()
+	}
+
+	for _, hook := range hooks {
+		ctx = hook.BeforeQuery(ctx, event)
+	}
+
+	event.RowsAffected, event.Err = run(ctx, result)
+	event.Duration = time.Since(event.Start)
+
+	for i := len(hooks) - 1; i >= 0; i-- {
+		hooks[i].AfterQuery(ctx, event)
+	}
+
+	return event.Err
+}
+
+func build(ctx context.Context, hooks []Hook, statement Statement) (Result, error) {
+	if len(hooks) == 0 {
+		return statement.ToSQL()
+	}
+
+	for _, hook := range hooks {
+		ctx = hook.BeforeBuild(ctx, statement)
+	}
+
+	start := time.Now()
+	result, err := statement.ToSQL()
+	event := BuildEvent{Statement: statement, Result: result, Duration: time.Since(start), Err: err}
+
+	for i := len(hooks) - 1; i >= 0; i-- {
+		hooks[i].AfterBuild(ctx, event)
+	}
+
+	return result, err
+}
+
+// SlogHook logs the executed statements and the statements that failed to build with slog
+type SlogHook struct {
+	NopHook
+
+	// Logger is slog.Default() if nil
+	Logger *slog.Logger
+	// Level is the level of the successful statements, errors are logged at error level
+	Level slog.Level
+	// SlowThreshold logs the statements that take longer at warn level, zero disables it
+	SlowThreshold time.Duration
+	// LogArgs logs the interpolated statement, with the Interpolate options, instead of just its sql
+	LogArgs     bool
+	Interpolate InterpolateOptions
+}
+
+func (h SlogHook) AfterBuild(ctx context.Context, event BuildEvent) {
+	if event.Err == nil {
+		return
+	}
+
+	h.logger().LogAttrs(ctx, slog.LevelError, "sqlcraft: build failed",
+		slog.String("statement", fmt.Sprintf("%T", event.Statement)),
+		slog.Any("error", event.Err),
+	)
+}
+
+func (h SlogHook) AfterQuery(ctx context.Context, event QueryEvent) {
+	level, message := h.Level, "sqlcraft: query"
+	switch {
+	case event.Err != nil:
+		level, message = slog.LevelError, "sqlcraft: query failed"
+	case h.SlowThreshold > 0 && event.Duration > h.SlowThreshold:
+		level, message = slog.LevelWarn, "sqlcraft: slow query"
+	}
+
+	logger := h.logger()
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+
+	sql := event.Result.Sql
+	if h.LogArgs {
+		sql = event.Result.Interpolate(h.Interpolate)
+	}
+
+	attrs := []slog.Attr{
+		slog.String("operation", string(event.Operation)),
+		slog.String("sql", sql),
+		slog.String("fingerprint", event.Fingerprint.Hash),
+		slog.Duration("duration", event.Duration),
+		slog.Int64("rows", event.RowsAffected),
+	}
+	if event.Err != nil {
+		attrs = append(attrs, slog.Any("error", event.Err))
+	}
+
+	logger.LogAttrs(ctx, level, message, attrs...)
+}
+
+func (h SlogHook) logger() *slog.Logger {
+	if h.Logger == nil {
+		return slog.Default()
+	}
+
+	return h.Logger
+}
@@ -0,0 +1,134 @@
+package sqlcraft
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"log/slog"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/techforge-lat/dafi/v2"
+)
+
+type spanKey struct{}
+
+// recordingHook records the events it receives and the span of their context
+type recordingHook struct {
+	name   string
+	events *[]string
+}
+
+func (h recordingHook) BeforeBuild(ctx context.Context, _ Statement) context.Context {
+	*h.events = append(*h.events, h.name+" before build")
+
+	return ctx
+}
+
+func (h recordingHook) AfterBuild(_ context.Context, event BuildEvent) {
+	*h.events = append(*h.events, h.name+" after build "+event.Result.Sql)
+}
+
+func (h recordingHook) BeforeQuery(ctx context.Context, event QueryEvent) context.Context {
+	*h.events = append(*h.events, h.name+" before "+string(event.Operation))
+
+	return context.WithValue(ctx, spanKey{}, h.name+" span")
+}
+
+func (h recordingHook) AfterQuery(ctx context.Context, event QueryEvent) {
+	span, _ := ctx.Value(spanKey{}).(string)
+	*h.events = append(*h.events, h.name+" after "+string(event.Operation)+" rows="+strconv.FormatInt(event.RowsAffected, 10)+" "+span)
+}
+
+func TestExecute_Hooks(t *testing.T) {
+	var events []string
+	ctx := WithHooks(context.Background(), recordingHook{name: "tracer", events: &events})
+	ctx = WithHooks(ctx, recordingHook{name: "logger", events: &events})
+
+	db := newFakeDB(t, &fakeDatabase{columns: []string{"id"}, rows: [][]driver.Value{{int64(1)}, {int64(2)}}})
+
+	if _, err := QueryAll[int64](ctx, db, Select("id").From("users")); err != nil {
+		t.Fatalf("QueryAll() error = %v", err)
+	}
+
+	want := []string{
+		"tracer before build",
+		"logger before build",
+		"logger after build SELECT id FROM users",
+		"tracer after build SELECT id FROM users",
+		"tracer before query",
+		"logger before query",
+		"logger after query rows=2 logger span",
+		"tracer after query rows=2 logger span",
+	}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("hook events = %q, want %q", events, want)
+	}
+}
+
+func TestExecute_BuildError(t *testing.T) {
+	var events []string
+	ctx := WithHooks(context.Background(), recordingHook{name: "hook", events: &events})
+	db := newFakeDB(t, &fakeDatabase{})
+
+	if _, err := Exec(ctx, db, DeleteFrom("users")); err != ErrMissingFilters {
+		t.Fatalf("Exec() error = %v, want %v", err, ErrMissingFilters)
+	}
+
+	want := []string{"hook before build", "hook after build "}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("hook events = %q, want %q", events, want)
+	}
+}
+
+func TestSlogHook(t *testing.T) {
+	buffer := bytes.Buffer{}
+	logger := slog.New(slog.NewTextHandler(&buffer, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	hook := SlogHook{
+		Logger:      logger,
+		Level:       slog.LevelDebug,
+		LogArgs:     true,
+		Interpolate: InterpolateOptions{RedactColumns: []string{"password"}},
+	}
+	ctx := WithHooks(context.Background(), hook)
+
+	database := &fakeDatabase{errs: []error{nil, fakePgError{code: "23505"}}}
+	db := newFakeDB(t, database)
+
+	query := Update("users").Set("password", "secret").Where(dafi.Filter{Field: "id", Value: 1})
+	if _, err := Exec(ctx, db, query); err != nil {
+		t.Fatalf("Exec() error = %v", err)
+	}
+
+	if _, err := Exec(ctx, db, Result{Sql: "INSERT INTO users (id) VALUES (1)"}); err == nil {
+		t.Fatalf("Exec() expected an error")
+	}
+
+	_, _ = Exec(ctx, db, DeleteFrom("users"))
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("SlogHook logged %d lines, want 3:\n%s", len(lines), buffer.String())
+	}
+
+	built, err := query.ToSQL()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wants := [][]string{
+		{"level=DEBUG", `msg="sqlcraft: query"`, "operation=exec", "password = '[REDACTED]'", "id = 1", "rows=1", "fingerprint=" + built.Fingerprint().Hash},
+		{"level=ERROR", `msg="sqlcraft: query failed"`, "pg error 23505"},
+		{"level=ERROR", `msg="sqlcraft: build failed"`, "statement=sqlcraft.DeleteQuery"},
+	}
+	for i, want := range wants {
+		for _, part := range want {
+			if !strings.Contains(lines[i], part) {
+				t.Errorf("SlogHook line %d = %s, missing %s", i, lines[i], part)
+			}
+		}
+	}
+}
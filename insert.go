@@ -1,7 +1,9 @@
 package sqlcraft
 
 import (
+	"context"
 	"errors"
+	"slices"
 	"strconv"
 	"strings"
 )
@@ -17,6 +19,12 @@ type InsertQuery struct {
 	columns          []string
 	returningColumns []string
 	values           []any
+
+	knownColumns []string
+
+	ctx     context.Context
+	audit   *Audit
+	tenancy tenancy
 }
 
 func InsertInto(tableName string) InsertQuery {
@@ -44,13 +52,41 @@ func (i InsertQuery) Returning(columns ...string) InsertQuery {
 	return i
 }
 
-func (i InsertQuery) ToSQL() (Result, error) {
-	if len(i.values) == 0 {
-		return Result{}, ErrEmptyValues
-	}
+// WithContext sets the context used to resolve the audit actor and the tenant
+func (i InsertQuery) WithContext(ctx context.Context) InsertQuery {
+	i.ctx = ctx
+
+	return i
+}
+
+// WithAudit fills the created and updated audit columns of every row,
+// columns already provided are left untouched
+func (i InsertQuery) WithAudit(audit Audit) InsertQuery {
+	i.audit = &audit
+
+	return i
+}
+
+// WithTenantScope sets the tenant column of every row to the tenant from ForTenant or the query context,
+// overwriting any provided value
+func (i InsertQuery) WithTenantScope(scope TenantScope) InsertQuery {
+	i.tenancy.scope = &scope
+
+	return i
+}
+
+// ForTenant sets the tenant of a query built WithTenantScope, instead of taking it from the context
+func (i InsertQuery) ForTenant(tenant any) InsertQuery {
+	i.tenancy.tenant = tenant
+	i.tenancy.isSet = true
+
+	return i
+}
 
-	if len(i.values)%len(i.columns) != 0 {
-		return Result{}, ErrMissMatchValues
+func (i InsertQuery) ToSQL() (Result, error) {
+	i, err := i.prepare()
+	if err != nil {
+		return Result{}, err
 	}
 
 	builder := strings.Builder{}
@@ -97,3 +133,97 @@ func (i InsertQuery) ToSQL() (Result, error) {
 		Args: i.values,
 	}, nil
 }
+
+// CopyData are the rows of an InsertQuery, as consumed by bulk loaders like the postgres COPY protocol
+type CopyData struct {
+	Table   string
+	Columns []string
+	Rows    [][]any
+}
+
+// ToCopyData returns the rows of the query with its tenant and audit columns,
+// the returning columns are ignored since a bulk load returns no rows
+func (i InsertQuery) ToCopyData() (CopyData, error) {
+	i, err := i.prepare()
+	if err != nil {
+		return CopyData{}, err
+	}
+
+	columnCount := len(i.columns)
+	rows := make([][]any, 0, len(i.values)/columnCount)
+	for start := 0; start < len(i.values); start += columnCount {
+		rows = append(rows, slices.Clone(i.values[start:start+columnCount]))
+	}
+
+	return CopyData{
+		Table:   i.table,
+		Columns: slices.Clone(i.columns),
+		Rows:    rows,
+	}, nil
+}
+
+// prepare validates the values and adds the columns filled by the query configuration
+func (i InsertQuery) prepare() (InsertQuery, error) {
+	if len(i.values) == 0 {
+		return InsertQuery{}, ErrEmptyValues
+	}
+
+	if len(i.columns) == 0 || len(i.values)%len(i.columns) != 0 {
+		return InsertQuery{}, ErrMissMatchValues
+	}
+
+	if err := checkColumns(i.knownColumns, i.columns); err != nil {
+		return InsertQuery{}, err
+	}
+
+	tenant, scoped, err := i.tenancy.value(i.ctx)
+	if err != nil {
+		return InsertQuery{}, err
+	}
+
+	if scoped {
+		i = i.withColumn(i.tenancy.scope.Column, tenant, true)
+	}
+
+	if i.audit != nil {
+		for _, audit := range i.audit.insertColumns(i.ctx) {
+			i = i.withColumn(audit.column, audit.value, false)
+		}
+	}
+
+	return i, nil
+}
+
+// withColumn sets the column to the value in every row, a column already provided
+// is only set when overwrite is true
+func (i InsertQuery) withColumn(column string, value any, overwrite bool) InsertQuery {
+	if column == "" {
+		return i
+	}
+
+	columnCount := len(i.columns)
+	if index := slices.Index(i.columns, column); index >= 0 {
+		if !overwrite {
+			return i
+		}
+
+		i.values = slices.Clone(i.values)
+		for valueIndex := index; valueIndex < len(i.values); valueIndex += columnCount {
+			i.values[valueIndex] = value
+		}
+
+		return i
+	}
+
+	rowCount := len(i.values) / columnCount
+	values := make([]any, 0, len(i.values)+rowCount)
+	for row := 0; row < rowCount; row++ {
+		values = append(values, i.values[row*columnCount:(row+1)*columnCount]...)
+		values = append(values, value)
+	}
+
+	i.columns = append(slices.Clone(i.columns), column)
+	i.values = values
+
+	return i
+}
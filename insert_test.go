@@ -82,3 +82,34 @@ func TestInsert_ToSql(t *testing.T) {
 		})
 	}
 }
+
+func TestInsert_ToCopyData(t *testing.T) {
+	query := InsertInto("users").
+		WithColumns("first_name", "email").
+		WithValues("Hernan", "hernan_rm@outlook.es").
+		WithValues("Brownie", "brownie@gmail.com").
+		WithTenantScope(TenantScope{Column: "tenant_id"}).
+		ForTenant(7).
+		Returning("id")
+
+	got, err := query.ToCopyData()
+	if err != nil {
+		t.Fatalf("ToCopyData() error = %v", err)
+	}
+
+	want := CopyData{
+		Table:   "users",
+		Columns: []string{"first_name", "email", "tenant_id"},
+		Rows: [][]any{
+			{"Hernan", "hernan_rm@outlook.es", 7},
+			{"Brownie", "brownie@gmail.com", 7},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ToCopyData() = %v, want %v", got, want)
+	}
+
+	if _, err := InsertInto("users").WithColumns("first_name").ToCopyData(); err != ErrEmptyValues {
+		t.Errorf("ToCopyData() error = %v, want %v", err, ErrEmptyValues)
+	}
+}
@@ -0,0 +1,379 @@
+package sqlcraft
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// interpolatedMarker prefixes every interpolated statement
+const interpolatedMarker = "/* sqlcraft: interpolated for debugging, NOT for execution */ "
+
+const redactedLiteral = "'[REDACTED]'"
+
+// InterpolateOptions configures how a Result is interpolated
+type InterpolateOptions struct {
+	// Dialect is the dialect of the result placeholders and literals, PostgreSQL by default
+	Dialect Dialect
+	// RedactColumns are the columns whose values are replaced by a placeholder text, e.g. "password".
+	// A value is matched to its column by the comparison, SET or INSERT it belongs to, ignoring table qualifiers,
+	// the values whose column cannot be told are redacted too
+	RedactColumns []string
+}
+
+// Interpolate returns the sql with its args written as escaped literals, for logs and debugging only,
+// the statement is prefixed with a comment saying it must not be executed,
+// use Sql and Args to run it so the values are never parsed as sql
+func (r Result) Interpolate(opts InterpolateOptions) string {
+	sql := r.Sql
+	if opts.Dialect == MySQL {
+		sql = numberQuestionMarks(sql)
+	}
+
+	redacted := map[string]struct{}{}
+	for _, column := range opts.RedactColumns {
+		redacted[unqualified(column)] = struct{}{}
+	}
+
+	var columns map[int]string
+	if len(redacted) > 0 {
+		columns = placeholderColumns(sql)
+	}
+
+	sql = replacePlaceholders(sql, func(number int) string {
+		if number < 1 || number > len(r.Args) {
+			return "$" + strconv.Itoa(number)
+		}
+
+		if len(redacted) > 0 {
+			column, ok := columns[number]
+			if _, isRedacted := redacted[unqualified(column)]; !ok || isRedacted {
+				return redactedLiteral
+			}
+		}
+
+		return literal(opts.Dialect, r.Args[number-1])
+	})
+
+	return interpolatedMarker + sql
+}
+
+// literal returns the value as a sql literal of the dialect
+func literal(dialect Dialect, value any) string {
+	if value == nil {
+		return "NULL"
+	}
+
+	switch v := value.(type) {
+	case driver.Valuer:
+		rv := reflect.ValueOf(v)
+		if rv.Kind() == reflect.Pointer && rv.IsNil() {
+			return "NULL"
+		}
+
+		driverValue, err := v.Value()
+		if err != nil {
+			return "NULL /* " + strings.ReplaceAll(err.Error(), "*/", "* /") + " */"
+		}
+
+		if _, ok := driverValue.(driver.Valuer); ok {
+			return quoteLiteral(dialect, fmt.Sprint(driverValue))
+		}
+
+		return literal(dialect, driverValue)
+	case string:
+		return quoteLiteral(dialect, v)
+	case []byte:
+		if dialect == MySQL {
+			return "X'" + hex.EncodeToString(v) + "'"
+		}
+
+		return `'\x` + hex.EncodeToString(v) + "'"
+	case time.Time:
+		if dialect == MySQL {
+			return quoteLiteral(dialect, v.Format("2006-01-02 15:04:05.999999"))
+		}
+
+		return quoteLiteral(dialect, v.Format("2006-01-02 15:04:05.999999Z07:00"))
+	case bool:
+		if v {
+			return "TRUE"
+		}
+
+		return "FALSE"
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Pointer:
+		if rv.IsNil() {
+			return "NULL"
+		}
+
+		return literal(dialect, rv.Elem().Interface())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return fmt.Sprint(value)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'g', -1, rv.Type().Bits())
+	case reflect.String:
+		return quoteLiteral(dialect, rv.String())
+	case reflect.Slice, reflect.Array:
+		items := make([]string, 0, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			items = append(items, literal(dialect, rv.Index(i).Interface()))
+		}
+
+		if dialect == MySQL {
+			return "(" + strings.Join(items, ", ") + ")"
+		}
+
+		return "ARRAY[" + strings.Join(items, ", ") + "]"
+	default:
+		if stringer, ok := value.(fmt.Stringer); ok {
+			return quoteLiteral(dialect, stringer.String())
+		}
+
+		return quoteLiteral(dialect, fmt.Sprint(value))
+	}
+}
+
+// quoteLiteral quotes a string, MySQL also treats backslashes as escapes
+func quoteLiteral(dialect Dialect, s string) string {
+	if dialect == MySQL {
+		s = strings.ReplaceAll(s, `\`, `\\`)
+	}
+
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// numberQuestionMarks rewrites the ? placeholders outside quoted strings into $n placeholders
+func numberQuestionMarks(sql string) string {
+	builder := strings.Builder{}
+	inQuote := false
+	number := 0
+	for i := 0; i < len(sql); i++ {
+		char := sql[i]
+		if char == '\'' {
+			inQuote = !inQuote
+		}
+
+		if char != '?' || inQuote {
+			builder.WriteByte(char)
+			continue
+		}
+
+		number++
+		builder.WriteString("$" + strconv.Itoa(number))
+	}
+
+	return builder.String()
+}
+
+type sqlTokenKind uint8
+
+const (
+	identifierToken sqlTokenKind = iota
+	placeholderToken
+	stringToken
+	symbolToken
+)
+
+type sqlToken struct {
+	kind  sqlTokenKind
+	text  string
+	value int
+}
+
+// tokenize splits a statement into the tokens needed to find the column of each placeholder
+func tokenize(sql string) []sqlToken {
+	var tokens []sqlToken
+	for i := 0; i < len(sql); {
+		char := sql[i]
+		switch {
+		case char == ' ' || char == '\t' || char == '\n' || char == '\r':
+			i++
+		case char == '\'':
+			end := i + 1
+			for end < len(sql) {
+				if sql[end] == '\'' {
+					if end+1 < len(sql) && sql[end+1] == '\'' {
+						end += 2
+						continue
+					}
+
+					break
+				}
+				end++
+			}
+
+			tokens = append(tokens, sqlToken{kind: stringToken, text: sql[i:min(end+1, len(sql))]})
+			i = end + 1
+		case char == '"' || char == '`':
+			end := strings.IndexByte(sql[i+1:], char)
+			if end < 0 {
+				end = len(sql) - i - 1
+			}
+
+			tokens = append(tokens, sqlToken{kind: identifierToken, text: sql[i+1 : i+1+end]})
+			i += end + 2
+		case char == '$' && i+1 < len(sql) && isDigit(sql[i+1]):
+			end := i + 1
+			for end < len(sql) && isDigit(sql[end]) {
+				end++
+			}
+
+			number, _ := strconv.Atoi(sql[i+1 : end])
+			tokens = append(tokens, sqlToken{kind: placeholderToken, text: sql[i:end], value: number})
+			i = end
+		case isIdentifierChar(char) && !isDigit(char):
+			end := i
+			for end < len(sql) && (isIdentifierChar(sql[end]) || sql[end] == '.') {
+				end++
+			}
+
+			tokens = append(tokens, sqlToken{kind: identifierToken, text: sql[i:end]})
+			i = end
+		case strings.IndexByte("<>=!", char) >= 0:
+			end := i
+			for end < len(sql) && strings.IndexByte("<>=!", sql[end]) >= 0 {
+				end++
+			}
+
+			tokens = append(tokens, sqlToken{kind: symbolToken, text: sql[i:end]})
+			i = end
+		default:
+			tokens = append(tokens, sqlToken{kind: symbolToken, text: string(char)})
+			i++
+		}
+	}
+
+	return tokens
+}
+
+// comparisonWords may stand between a column and its value, e.g. col NOT IN ($1), col IS DISTINCT FROM $1
+var comparisonWords = map[string]struct{}{
+	"in": {}, "not": {}, "like": {}, "ilike": {}, "is": {}, "distinct": {}, "from": {},
+	"between": {}, "and": {}, "any": {}, "all": {}, "array": {}, "similar": {}, "to": {},
+}
+
+// placeholderColumns returns the column each placeholder number is compared with, set to or inserted into,
+// it is a best effort used to redact values, placeholders whose column is unclear are not included
+func placeholderColumns(sql string) map[int]string {
+	tokens := tokenize(sql)
+	columns := map[int]string{}
+
+	if insertColumns, valuesAt, ok := insertShape(tokens); ok {
+		position, depth := 0, 0
+		for _, token := range tokens[valuesAt:] {
+			switch {
+			case token.text == "(":
+				depth++
+				if depth == 1 {
+					position = 0
+				}
+			case token.text == ")":
+				depth--
+			case token.text == "," && depth == 1:
+				position++
+			case token.kind == placeholderToken && depth >= 1 && position < len(insertColumns):
+				columns[token.value] = insertColumns[position]
+			}
+
+			if depth == 0 && token.kind == identifierToken && !strings.EqualFold(token.text, "values") {
+				break
+			}
+		}
+	}
+
+	for i, token := range tokens {
+		if token.kind != placeholderToken {
+			continue
+		}
+
+		if _, ok := columns[token.value]; ok {
+			continue
+		}
+
+		if column := comparedColumn(tokens[:i]); column != "" {
+			columns[token.value] = column
+		}
+	}
+
+	return columns
+}
+
+// comparedColumn walks back from a placeholder to the column on the other side of its operator
+func comparedColumn(before []sqlToken) string {
+	for i := len(before) - 1; i >= 0; i-- {
+		token := before[i]
+		switch token.kind {
+		case placeholderToken, stringToken:
+			continue
+		case symbolToken:
+			if token.text == "(" || token.text == "," || strings.IndexAny(token.text, "<>=!") >= 0 {
+				continue
+			}
+
+			return ""
+		case identifierToken:
+			if _, ok := comparisonWords[strings.ToLower(token.text)]; ok {
+				continue
+			}
+
+			// a function call like COALESCE($1, password) is part of the value, the column is further back
+			if i+1 < len(before) && before[i+1].text == "(" {
+				continue
+			}
+
+			return token.text
+		}
+	}
+
+	return ""
+}
+
+// insertShape returns the columns of an INSERT INTO t (columns) VALUES statement and the index of its VALUES token
+func insertShape(tokens []sqlToken) ([]string, int, bool) {
+	if len(tokens) < 4 || !strings.EqualFold(tokens[0].text, "insert") || !strings.EqualFold(tokens[1].text, "into") {
+		return nil, 0, false
+	}
+
+	i := 3
+	if i >= len(tokens) || tokens[i].text != "(" {
+		return nil, 0, false
+	}
+
+	var columns []string
+	for i++; i < len(tokens) && tokens[i].text != ")"; i++ {
+		if tokens[i].kind == identifierToken {
+			columns = append(columns, tokens[i].text)
+		}
+	}
+
+	if i+1 >= len(tokens) || !strings.EqualFold(tokens[i+1].text, "values") {
+		return nil, 0, false
+	}
+
+	return columns, i + 1, true
+}
+
+func unqualified(column string) string {
+	if i := strings.LastIndexByte(column, '.'); i >= 0 {
+		column = column[i+1:]
+	}
+
+	return strings.ToLower(column)
+}
+
+func isDigit(char byte) bool {
+	return char >= '0' && char <= '9'
+}
+
+func isIdentifierChar(char byte) bool {
+	return char == '_' || isDigit(char) || (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z')
+}
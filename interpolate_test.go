@@ -0,0 +1,114 @@
+package sqlcraft
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/techforge-lat/dafi/v2"
+)
+
+func TestResult_Interpolate(t *testing.T) {
+	createdAt := time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)
+	id := uuid.MustParse("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+	var nilName *string
+
+	tests := []struct {
+		name   string
+		result Result
+		opts   InterpolateOptions
+		want   string
+	}{
+		{
+			name: "postgres literals",
+			result: Result{
+				Sql:  "SELECT * FROM users WHERE name = $1 AND id = $2 AND created_at > $3 AND active = $4 AND age > $5 AND score < $6 AND nickname = $7 AND avatar = $8 AND tags = $9",
+				Args: []any{"O'Brien", id, createdAt, true, 30, 4.5, nilName, []byte{0xde, 0xad}, []string{"a", "b"}},
+			},
+			want: interpolatedMarker + "SELECT * FROM users WHERE name = 'O''Brien' AND id = '6ba7b810-9dad-11d1-80b4-00c04fd430c8' AND created_at > '2024-05-01 10:30:00Z' AND active = TRUE AND age > 30 AND score < 4.5 AND nickname = NULL AND avatar = '\\xdead' AND tags = ARRAY['a', 'b']",
+		},
+		{
+			name: "mysql literals",
+			result: Result{
+				Sql:  "UPDATE users SET name = ?, bio = ?, avatar = ?, updated_at = ? WHERE id IN (?, ?) AND note = '?'",
+				Args: []any{`it's \ fine`, nil, []byte{0xde, 0xad}, createdAt, 1, 2},
+			},
+			opts: InterpolateOptions{Dialect: MySQL},
+			want: interpolatedMarker + `UPDATE users SET name = 'it''s \\ fine', bio = NULL, avatar = X'dead', updated_at = '2024-05-01 10:30:00' WHERE id IN (1, 2) AND note = '?'`,
+		},
+		{
+			name: "placeholders inside strings and missing args are kept",
+			result: Result{
+				Sql:  "SELECT '$1' AS literal, $1 AS value, $2 AS missing",
+				Args: []any{Some(7)},
+			},
+			want: interpolatedMarker + "SELECT '$1' AS literal, 7 AS value, $2 AS missing",
+		},
+		{
+			name: "redact compared and set columns",
+			result: Result{
+				Sql:  "UPDATE users SET password = $1, name = $2 WHERE u.token IN ($3, $4) AND id = $5",
+				Args: []any{"secret", "hernan", "t1", "t2", 1},
+			},
+			opts: InterpolateOptions{RedactColumns: []string{"password", "TOKEN"}},
+			want: interpolatedMarker + "UPDATE users SET password = '[REDACTED]', name = 'hernan' WHERE u.token IN ('[REDACTED]', '[REDACTED]') AND id = 1",
+		},
+		{
+			name: "redact unclear columns",
+			result: Result{
+				Sql:  "UPDATE users SET views = views + $1, name = lower($2) WHERE id = $3",
+				Args: []any{1, "Hernan", 1},
+			},
+			opts: InterpolateOptions{RedactColumns: []string{"password"}},
+			want: interpolatedMarker + "UPDATE users SET views = views + '[REDACTED]', name = lower('Hernan') WHERE id = 1",
+		},
+		{
+			name: "redact inserted columns",
+			result: Result{
+				Sql:  "INSERT INTO users (email, password) VALUES ($1, $2), ($3, $4) RETURNING id",
+				Args: []any{"a@b.c", "secret", "d@e.f", "secret2"},
+			},
+			opts: InterpolateOptions{RedactColumns: []string{"users.password"}},
+			want: interpolatedMarker + "INSERT INTO users (email, password) VALUES ('a@b.c', '[REDACTED]'), ('d@e.f', '[REDACTED]') RETURNING id",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.result.Interpolate(tt.opts); got != tt.want {
+				t.Errorf("Interpolate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResult_Interpolate_BuiltQuery(t *testing.T) {
+	result, err := Select("id", "email").
+		From("users").
+		Where(dafi.Filter{Field: "email", Value: "hernan@outlook.es"}, dafi.Filter{Field: "password", Value: "secret"}).
+		ToSQL()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := interpolatedMarker + "SELECT id, email FROM users WHERE email = 'hernan@outlook.es' AND password = '[REDACTED]'"
+	if got := result.Interpolate(InterpolateOptions{RedactColumns: []string{"password"}}); got != want {
+		t.Errorf("Interpolate() = %v, want %v", got, want)
+	}
+}
+
+func TestResult_Interpolate_PartialUpdate(t *testing.T) {
+	result, err := Update("users").
+		WithColumns("email", "password").
+		WithValues("hernan@outlook.es", "secret").
+		WithPartialUpdate().
+		Where(dafi.Filter{Field: "id", Value: 1}).
+		ToSQL()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := interpolatedMarker + "UPDATE users SET email = COALESCE('hernan@outlook.es', email), password = COALESCE('[REDACTED]', password) WHERE id = 1"
+	if got := result.Interpolate(InterpolateOptions{RedactColumns: []string{"password"}}); got != want {
+		t.Errorf("Interpolate() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,144 @@
+package sqlcraft
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"maps"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// mappingKey identifies a cached mapping
+type mappingKey struct {
+	structType reflect.Type
+	domainTag  string
+	columnTag  string
+}
+
+// mappingByKey caches the mappings of every struct type and tags pair
+var mappingByKey sync.Map
+
+var (
+	timeType    = reflect.TypeOf(time.Time{})
+	valuerType  = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+)
+
+// SQLColumnByDomainFieldOf builds the domain field to sql column mapping of a struct from a pair of tags,
+// e.g. SQLColumnByDomainFieldOf(User{}, "json", "db")
+//
+// Fields without the column tag are skipped, fields without the domain tag use their go name.
+// Embedded structs are flattened and nested structs are prefixed: a field `json:"city" db:"city"`
+// inside a field `json:"address" db:"address"` is mapped as "address.city" -> "address_city".
+// Structs stored in a single column (time.Time, driver.Valuer and sql.Scanner implementations) are not nested.
+// The mapping is cached by type, the returned map is a copy that can be modified
+func SQLColumnByDomainFieldOf(v any, domainTag, columnTag string) (map[string]string, error) {
+	structType := reflect.TypeOf(v)
+	for structType != nil && structType.Kind() == reflect.Pointer {
+		structType = structType.Elem()
+	}
+
+	if structType == nil || structType.Kind() != reflect.Struct {
+		return nil, ErrInvalidStruct
+	}
+
+	key := mappingKey{structType: structType, domainTag: domainTag, columnTag: columnTag}
+	if cached, ok := mappingByKey.Load(key); ok {
+		return maps.Clone(cached.(map[string]string)), nil
+	}
+
+	mapping := map[string]string{}
+	appendMapping(mapping, structType, key, "", "")
+	mappingByKey.Store(key, mapping)
+
+	return maps.Clone(mapping), nil
+}
+
+// MustSQLColumnByDomainFieldOf is like SQLColumnByDomainFieldOf but panics when v is not a struct
+func MustSQLColumnByDomainFieldOf(v any, domainTag, columnTag string) map[string]string {
+	mapping, err := SQLColumnByDomainFieldOf(v, domainTag, columnTag)
+	if err != nil {
+		panic(err)
+	}
+
+	return mapping
+}
+
+func appendMapping(mapping map[string]string, structType reflect.Type, key mappingKey, domainPrefix, columnPrefix string) {
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if !field.IsExported() && !field.Anonymous {
+			continue
+		}
+
+		domainName, hasDomainTag := tagName(field, key.domainTag)
+		columnName, hasColumnTag := tagName(field, key.columnTag)
+		if domainName == "-" || columnName == "-" {
+			continue
+		}
+
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+
+		if fieldType.Kind() == reflect.Struct && !isColumnStruct(fieldType) {
+			switch {
+			case field.Anonymous && !hasDomainTag:
+				appendMapping(mapping, fieldType, key, domainPrefix, columnPrefix)
+			case field.IsExported():
+				if !hasDomainTag {
+					domainName = field.Name
+				}
+
+				nestedColumnPrefix := columnPrefix
+				if hasColumnTag {
+					nestedColumnPrefix += columnName + "_"
+				}
+
+				appendMapping(mapping, fieldType, key, domainPrefix+domainName+".", nestedColumnPrefix)
+			}
+
+			continue
+		}
+
+		if !hasColumnTag || !field.IsExported() {
+			continue
+		}
+
+		if !hasDomainTag {
+			domainName = field.Name
+		}
+
+		if _, exists := mapping[domainPrefix+domainName]; exists {
+			continue
+		}
+
+		mapping[domainPrefix+domainName] = columnPrefix + columnName
+	}
+}
+
+// tagName returns the name of a tag, options after a comma are ignored
+func tagName(field reflect.StructField, tag string) (string, bool) {
+	value, ok := field.Tag.Lookup(tag)
+	if !ok {
+		return "", false
+	}
+
+	name, _, _ := strings.Cut(value, ",")
+
+	return name, name != ""
+}
+
+// isColumnStruct reports whether a struct type is stored in a single column
+func isColumnStruct(structType reflect.Type) bool {
+	if structType == timeType {
+		return true
+	}
+
+	pointerType := reflect.PointerTo(structType)
+
+	return structType.Implements(valuerType) || pointerType.Implements(valuerType) || pointerType.Implements(scannerType)
+}
@@ -0,0 +1,61 @@
+package sqlcraft
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type mappingAddress struct {
+	City    string `json:"city" db:"city"`
+	ZipCode string `json:"zipCode" db:"zip_code"`
+}
+
+type mappingBilling struct {
+	Country string `json:"country" db:"billing_country"`
+}
+
+type mappingTimestamps struct {
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+}
+
+type mappingUser struct {
+	mappingTimestamps
+	ID       int              `json:"id" db:"id"`
+	Email    string           `json:"email" db:"email"`
+	Nickname Optional[string] `json:"nickname" db:"nickname"`
+	Password string           `json:"-" db:"password"`
+	Role     string           `json:"role"`
+	Address  *mappingAddress  `json:"address" db:"address"`
+	Billing  mappingBilling   `json:"billing"`
+}
+
+func TestSQLColumnByDomainFieldOf(t *testing.T) {
+	want := map[string]string{
+		"createdAt":       "created_at",
+		"id":              "id",
+		"email":           "email",
+		"nickname":        "nickname",
+		"address.city":    "address_city",
+		"address.zipCode": "address_zip_code",
+		"billing.country": "billing_country",
+	}
+
+	got, err := SQLColumnByDomainFieldOf(&mappingUser{}, "json", "db")
+	if err != nil {
+		t.Fatalf("SQLColumnByDomainFieldOf() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SQLColumnByDomainFieldOf() = %v, want %v", got, want)
+	}
+
+	got["id"] = "changed"
+	cached := MustSQLColumnByDomainFieldOf(mappingUser{}, "json", "db")
+	if !reflect.DeepEqual(cached, want) {
+		t.Errorf("SQLColumnByDomainFieldOf() cached = %v, want %v", cached, want)
+	}
+
+	if _, err := SQLColumnByDomainFieldOf("users", "json", "db"); err == nil {
+		t.Errorf("SQLColumnByDomainFieldOf() expected an error for a non struct value")
+	}
+}
@@ -0,0 +1,110 @@
+package sqlcraft
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+)
+
+type optionalState uint8
+
+const (
+	optionalUnset optionalState = iota
+	optionalNull
+	optionalSet
+)
+
+// Optional is a tri-state value: unset, null or set.
+// Its zero value is unset, which makes it a natural fit for PATCH payloads:
+// a missing JSON key stays unset, a JSON null becomes null and anything else is set
+//
+// UpdateQuery omits unset columns, writes col = NULL for null ones and binds the set values
+type Optional[T any] struct {
+	state optionalState
+	value T
+}
+
+// Some returns a set Optional holding the given value
+func Some[T any](value T) Optional[T] {
+	return Optional[T]{state: optionalSet, value: value}
+}
+
+// Null returns an Optional explicitly set to NULL
+func Null[T any]() Optional[T] {
+	return Optional[T]{state: optionalNull}
+}
+
+func (o Optional[T]) IsUnset() bool {
+	return o.state == optionalUnset
+}
+
+func (o Optional[T]) IsNull() bool {
+	return o.state == optionalNull
+}
+
+// Get returns the value and true when the Optional is set
+func (o Optional[T]) Get() (T, bool) {
+	return o.value, o.state == optionalSet
+}
+
+// Value implements driver.Valuer, unset and null are both stored as NULL
+func (o Optional[T]) Value() (driver.Value, error) {
+	if o.state != optionalSet {
+		return nil, nil
+	}
+
+	return driver.DefaultParameterConverter.ConvertValue(o.value)
+}
+
+func (o Optional[T]) MarshalJSON() ([]byte, error) {
+	if o.state != optionalSet {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(o.value)
+}
+
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		*o = Null[T]()
+
+		return nil
+	}
+
+	var value T
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	*o = Some(value)
+
+	return nil
+}
+
+// Scan implements sql.Scanner, a NULL column becomes null and anything else is set
+func (o *Optional[T]) Scan(src any) error {
+	var value sql.Null[T]
+	if err := value.Scan(src); err != nil {
+		return err
+	}
+
+	if !value.Valid {
+		*o = Null[T]()
+
+		return nil
+	}
+
+	*o = Some(value.V)
+
+	return nil
+}
+
+func (o Optional[T]) optional() (optionalState, any) {
+	return o.state, o.value
+}
+
+// optional lets the builders read an Optional without knowing its type
+type optional interface {
+	optional() (optionalState, any)
+}
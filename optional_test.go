@@ -0,0 +1,64 @@
+package sqlcraft
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestOptional_UnmarshalJSON(t *testing.T) {
+	type patch struct {
+		Name     Optional[string] `json:"name"`
+		Nickname Optional[string] `json:"nickname"`
+		Salary   Optional[int]    `json:"salary"`
+	}
+
+	var got patch
+	if err := json.Unmarshal([]byte(`{"nickname": null, "salary": 4000}`), &got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	want := patch{
+		Name:     Optional[string]{},
+		Nickname: Null[string](),
+		Salary:   Some(4000),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("json.Unmarshal() = %v, want %v", got, want)
+	}
+
+	if !got.Name.IsUnset() || !got.Nickname.IsNull() {
+		t.Errorf("unexpected states: name unset %v, nickname null %v", got.Name.IsUnset(), got.Nickname.IsNull())
+	}
+
+	if salary, ok := got.Salary.Get(); !ok || salary != 4000 {
+		t.Errorf("Optional.Get() = %v, %v, want 4000, true", salary, ok)
+	}
+}
+
+func TestOptional_Scan(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     any
+		want    Optional[int64]
+		wantErr bool
+	}{
+		{name: "null", src: nil, want: Null[int64]()},
+		{name: "value", src: int64(4000), want: Some[int64](4000)},
+		{name: "converted value", src: []byte("4000"), want: Some[int64](4000)},
+		{name: "invalid value", src: "four", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Optional[int64]
+			err := got.Scan(tt.src)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Optional.Scan() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Optional.Scan() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,232 @@
+package sqlcraft
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/techforge-lat/dafi/v2"
+	"github.com/techforge-lat/errortrace/v2"
+	"github.com/techforge-lat/errortrace/v2/errtype"
+)
+
+var (
+	ErrFieldNotFilterable = errors.New("field is not filterable")
+	ErrFieldNotSortable   = errors.New("field is not sortable")
+	ErrFieldNotGroupable  = errors.New("field is not groupable")
+	ErrOperatorNotAllowed = errors.New("operator not allowed for field")
+	ErrInvalidValueType   = errors.New("invalid value type for field")
+	ErrInListTooLong      = errors.New("too many values in IN list")
+)
+
+// FieldPolicy describes what a client is allowed to do with a domain field,
+// a field is not filterable, sortable nor groupable unless it is explicitly allowed
+type FieldPolicy struct {
+	// Column is the sql column of the domain field, if empty the domain field name is used
+	Column string
+	// Operators are the allowed filter operators, if empty every operator is allowed
+	Operators []dafi.FilterOperator
+	// ValueType is the expected type of the filter value (or of each value for IN and NOT IN), if nil any type is allowed,
+	// numbers of the same kind family are accepted, e.g. an int64 for an int
+	ValueType reflect.Type
+	// ColumnType coerces string values (like the ones from a query string) before checking the ValueType,
+	// it takes precedence over the WhereOptions ColumnTypes
+	ColumnType ColumnType
+	// MaxInLength is the max number of values for IN and NOT IN, if zero there is no limit
+	MaxInLength int
+
+	Filterable bool
+	Sortable   bool
+	Groupable  bool
+}
+
+// FieldPolicies holds the policy of every domain field a client can use, by domain field name
+type FieldPolicies map[string]FieldPolicy
+
+func (f FieldPolicy) column(field string) string {
+	if f.Column == "" {
+		return field
+	}
+
+	return f.Column
+}
+
+func (p FieldPolicies) policy(field string) (FieldPolicy, error) {
+	policy, ok := p[field]
+	if !ok {
+		return FieldPolicy{}, errortrace.
+			OnError(ErrInvalidFieldName).
+			WithCode(errtype.UnprocessableEntity).
+			WithMessage(fmt.Sprintf("field %q not found", field))
+	}
+
+	return policy, nil
+}
+
+// SortColumn returns the sql column of a sortable domain field
+func (p FieldPolicies) SortColumn(field string) (string, error) {
+	policy, err := p.policy(field)
+	if err != nil {
+		return "", err
+	}
+
+	if !policy.Sortable {
+		return "", errortrace.
+			OnError(ErrFieldNotSortable).
+			WithCode(errtype.UnprocessableEntity).
+			WithMessage(fmt.Sprintf("field %q is not sortable", field))
+	}
+
+	return policy.column(field), nil
+}
+
+// GroupColumn returns the sql column of a groupable domain field
+func (p FieldPolicies) GroupColumn(field string) (string, error) {
+	policy, err := p.policy(field)
+	if err != nil {
+		return "", err
+	}
+
+	if !policy.Groupable {
+		return "", errortrace.
+			OnError(ErrFieldNotGroupable).
+			WithCode(errtype.UnprocessableEntity).
+			WithMessage(fmt.Sprintf("field %q is not groupable", field))
+	}
+
+	return policy.column(field), nil
+}
+
+// FilterColumn returns the sql column of the filter field,
+// if the field, operator or value are not allowed by its policy it will return an error
+func (p FieldPolicies) FilterColumn(filter dafi.Filter) (string, error) {
+	field := string(filter.Field)
+
+	policy, err := p.policy(field)
+	if err != nil {
+		return "", err
+	}
+
+	if !policy.Filterable {
+		return "", errortrace.
+			OnError(ErrFieldNotFilterable).
+			WithCode(errtype.UnprocessableEntity).
+			WithMessage(fmt.Sprintf("field %q is not filterable", field))
+	}
+
+	operator := filter.Operator
+	if operator == "" {
+		operator = dafi.Equal
+	}
+
+	if len(policy.Operators) > 0 && !slices.Contains(policy.Operators, operator) {
+		return "", errortrace.
+			OnError(ErrOperatorNotAllowed).
+			WithCode(errtype.UnprocessableEntity).
+			WithMessage(fmt.Sprintf("operator %q is not allowed for field %q", operator, field))
+	}
+
+	switch operator {
+	case dafi.Is, dafi.IsNot, dafi.IsNull, dafi.IsNotNull, dafi.Default:
+		return policy.column(field), nil
+	case dafi.In, dafi.NotIn:
+		values := inValues(filter.Value)
+		if policy.MaxInLength > 0 && len(values) > policy.MaxInLength {
+			return "", errortrace.
+				OnError(ErrInListTooLong).
+				WithCode(errtype.UnprocessableEntity).
+				WithMessage(fmt.Sprintf("field %q accepts at most %d values, got %d", field, policy.MaxInLength, len(values)))
+		}
+
+		for _, value := range values {
+			if err := policy.checkValueType(field, value); err != nil {
+				return "", err
+			}
+		}
+	default:
+		if err := policy.checkValueType(field, filter.Value); err != nil {
+			return "", err
+		}
+	}
+
+	return policy.column(field), nil
+}
+
+func (f FieldPolicy) checkValueType(field string, value any) error {
+	if f.ValueType == nil || value == nil {
+		return nil
+	}
+
+	if _, ok := value.(ColumnRef); ok {
+		return nil
+	}
+
+	if f.ColumnType != "" {
+		coerced, err := CoerceValue(f.ColumnType, value)
+		if err != nil {
+			return err
+		}
+
+		value = coerced
+	}
+
+	valueType := reflect.TypeOf(value)
+	if valueType.AssignableTo(f.ValueType) || kindFamily(valueType) != "" && kindFamily(valueType) == kindFamily(f.ValueType) {
+		return nil
+	}
+
+	return errortrace.
+		OnError(ErrInvalidValueType).
+		WithCode(errtype.UnprocessableEntity).
+		WithMessage(fmt.Sprintf("field %q expects a value of type %s, got %T", field, f.ValueType, value))
+}
+
+// kindFamily groups the numeric kinds whose values the drivers store the same way
+func kindFamily(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "int"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "uint"
+	case reflect.Float32, reflect.Float64:
+		return "float"
+	default:
+		return ""
+	}
+}
+
+// inValues returns the values of an IN filter, a slice or a comma separated string (empty means no values),
+// any other value is taken as a list of one value
+func inValues(value any) []any {
+	if value == nil {
+		return nil
+	}
+
+	valSlice := reflect.ValueOf(value)
+	if valSlice.Kind() == reflect.Slice {
+		values := make([]any, 0, valSlice.Len())
+		for i := 0; i < valSlice.Len(); i++ {
+			values = append(values, valSlice.Index(i).Interface())
+		}
+
+		return values
+	}
+
+	str, ok := value.(string)
+	if !ok {
+		return []any{value}
+	}
+
+	values := []any{}
+	if str == "" {
+		return values
+	}
+
+	for _, v := range strings.Split(str, ",") {
+		values = append(values, v)
+	}
+
+	return values
+}
@@ -0,0 +1,209 @@
+package sqlcraft
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/techforge-lat/dafi/v2"
+	"github.com/techforge-lat/errortrace/v2"
+)
+
+func TestWhereWithOptions_FieldPolicies(t *testing.T) {
+	policies := FieldPolicies{
+		"email": {
+			Column:     "users.email",
+			Operators:  []dafi.FilterOperator{dafi.Equal, dafi.In},
+			ValueType:  reflect.TypeOf(""),
+			Filterable: true,
+		},
+		"id": {
+			Column:      "users.id",
+			ValueType:   reflect.TypeOf(0),
+			MaxInLength: 2,
+			Filterable:  true,
+		},
+		"password": {
+			Column: "users.password",
+		},
+	}
+
+	tests := []struct {
+		name    string
+		filters dafi.Filters
+		want    Result
+		wantErr error
+	}{
+		{
+			name:    "maps allowed field",
+			filters: dafi.Filters{{Field: "email", Value: "hernan_rm@outlook.es"}},
+			want: Result{
+				Sql:  " WHERE users.email = $1",
+				Args: []any{"hernan_rm@outlook.es"},
+			},
+		},
+		{
+			name:    "in list within limit",
+			filters: dafi.Filters{{Field: "id", Operator: dafi.In, Value: []int{1, 2}}},
+			want: Result{
+				Sql:  " WHERE users.id IN ($1, $2)",
+				Args: []any{1, 2},
+			},
+		},
+		{
+			name:    "unknown field",
+			filters: dafi.Filters{{Field: "nickname", Value: "hernan"}},
+			wantErr: ErrInvalidFieldName,
+		},
+		{
+			name:    "field not filterable",
+			filters: dafi.Filters{{Field: "password", Value: "secret"}},
+			wantErr: ErrFieldNotFilterable,
+		},
+		{
+			name:    "operator not allowed",
+			filters: dafi.Filters{{Field: "email", Operator: dafi.Contains, Value: "hernan"}},
+			wantErr: ErrOperatorNotAllowed,
+		},
+		{
+			name:    "invalid value type",
+			filters: dafi.Filters{{Field: "id", Value: "1"}},
+			wantErr: ErrInvalidValueType,
+		},
+		{
+			name:    "in list too long",
+			filters: dafi.Filters{{Field: "id", Operator: dafi.In, Value: []int{1, 2, 3}}},
+			wantErr: ErrInListTooLong,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := WhereWithOptions(0, WhereOptions{FieldPolicies: policies}, tt.filters...)
+			if !errortrace.Is(err, tt.wantErr) {
+				t.Errorf("WhereWithOptions() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("WhereWithOptions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWhereWithOptions_FieldPoliciesCoercion(t *testing.T) {
+	options := WhereOptions{
+		FieldPolicies: FieldPolicies{
+			"num": {ValueType: reflect.TypeOf(0), Filterable: true},
+			"age": {ValueType: reflect.TypeOf(0), ColumnType: IntColumnType, Filterable: true},
+			"id":  {ValueType: reflect.TypeOf(0), Filterable: true},
+		},
+		ColumnTypes: ColumnTypes{"num": IntColumnType},
+	}
+
+	tests := []struct {
+		name    string
+		filters dafi.Filters
+		want    Result
+		wantErr error
+	}{
+		{
+			name:    "coerced by column types",
+			filters: dafi.Filters{{Field: "num", Value: "5"}},
+			want:    Result{Sql: " WHERE num = $1", Args: []any{int64(5)}},
+		},
+		{
+			name:    "coerced by policy column type",
+			filters: dafi.Filters{{Field: "age", Operator: dafi.In, Value: "18,21"}},
+			want:    Result{Sql: " WHERE age IN ($1, $2)", Args: []any{int64(18), int64(21)}},
+		},
+		{
+			name:    "same numeric family",
+			filters: dafi.Filters{{Field: "id", Value: int32(7)}},
+			want:    Result{Sql: " WHERE id = $1", Args: []any{int32(7)}},
+		},
+		{
+			name:    "invalid coerced value",
+			filters: dafi.Filters{{Field: "age", Value: "old"}},
+			wantErr: ErrInvalidValue,
+		},
+		{
+			name:    "string without coercion",
+			filters: dafi.Filters{{Field: "id", Value: "7"}},
+			wantErr: ErrInvalidValueType,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := WhereWithOptions(0, options, tt.filters...)
+			if !errortrace.Is(err, tt.wantErr) {
+				t.Errorf("WhereWithOptions() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("WhereWithOptions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := options.FieldPolicies.FilterColumn(dafi.Filter{Field: "age", Value: "30"}); err != nil {
+		t.Errorf("FilterColumn() error = %v, want nil", err)
+	}
+}
+
+func TestSelectQuery_ToSQL_FieldPolicies(t *testing.T) {
+	policies := FieldPolicies{
+		"createdAt": {Column: "created_at", Sortable: true},
+		"country":   {Column: "country", Groupable: true, Filterable: true},
+	}
+
+	tests := []struct {
+		name    string
+		query   SelectQuery
+		want    Result
+		wantErr error
+	}{
+		{
+			name:  "sort and group by allowed fields",
+			query: Select("country", "count(*)").From("users").FieldPolicies(policies).Where(dafi.Filter{Field: "country", Value: "PE"}).GroupBy("country").OrderBy(dafi.Sort{Field: "createdAt", Type: dafi.Desc}),
+			want: Result{
+				Sql:  "SELECT country, count(*) FROM users WHERE country = $1 GROUP BY country ORDER BY created_at DESC",
+				Args: []any{"PE"},
+			},
+		},
+		{
+			name:    "field not sortable",
+			query:   Select("country").From("users").FieldPolicies(policies).OrderBy(dafi.Sort{Field: "country"}),
+			wantErr: ErrFieldNotSortable,
+		},
+		{
+			name:    "field not groupable",
+			query:   Select("country").From("users").FieldPolicies(policies).GroupBy("createdAt"),
+			wantErr: ErrFieldNotGroupable,
+		},
+		{
+			name:    "field not filterable",
+			query:   Select("country").From("users").FieldPolicies(policies).Where(dafi.Filter{Field: "createdAt", Value: "2024-01-01"}),
+			wantErr: ErrFieldNotFilterable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.query.ToSQL()
+			if !errortrace.Is(err, tt.wantErr) {
+				t.Errorf("SelectQuery.ToSQL() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SelectQuery.ToSQL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
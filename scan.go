@@ -0,0 +1,256 @@
+package sqlcraft
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/techforge-lat/errortrace/v2"
+	"github.com/techforge-lat/errortrace/v2/errtype"
+)
+
+var ErrUnknownResultColumn = errors.New("result column has no matching struct field")
+
+// Rows are the rows of a query result, *sql.Rows implements it
+type Rows interface {
+	Columns() ([]string, error)
+	Next() bool
+	Scan(dest ...any) error
+	Err() error
+	Close() error
+}
+
+// ScanOne scans the first row into a T and closes the rows, if there are no rows it returns a not found error
+//
+// When T is a struct the result columns are matched to its fields by `db` tag, including the fields of
+// its embedded structs, and a NULL column leaves its field with the zero value, so the null AS col
+// placeholders of RequiredColumns can be scanned into non pointer fields.
+// Any other T is scanned from a single column
+func ScanOne[T any](rows Rows) (T, error) {
+	var item T
+	defer rows.Close()
+
+	scanner, err := newRowScanner(reflect.TypeOf(item), rows)
+	if err != nil {
+		return item, err
+	}
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return item, WrapDBError(err)
+		}
+
+		return item, WrapDBError(sql.ErrNoRows)
+	}
+
+	if err := scanner.scan(rows, reflect.ValueOf(&item).Elem()); err != nil {
+		return item, err
+	}
+
+	return item, nil
+}
+
+// ScanAll scans every row into a slice of T and closes the rows, see ScanOne for how the columns are matched
+func ScanAll[T any](rows Rows) ([]T, error) {
+	defer rows.Close()
+
+	scanner, err := newRowScanner(reflect.TypeOf((*T)(nil)).Elem(), rows)
+	if err != nil {
+		return nil, err
+	}
+
+	items := []T{}
+	for rows.Next() {
+		var item T
+		if err := scanner.scan(rows, reflect.ValueOf(&item).Elem()); err != nil {
+			return nil, err
+		}
+
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, WrapDBError(err)
+	}
+
+	return items, nil
+}
+
+// QueryOne builds and executes a statement and scans its first row into a T
+func QueryOne[T any](ctx context.Context, db DB, statement Statement) (T, error) {
+	var item T
+	err := Execute(ctx, QueryOperation, statement, func(ctx context.Context, result Result) (int64, error) {
+		rows, err := db.QueryContext(ctx, result.Sql, result.Args...)
+		if err != nil {
+			return -1, WrapDBError(err)
+		}
+
+		item, err = ScanOne[T](rows)
+		if err != nil {
+			return 0, err
+		}
+
+		return 1, nil
+	})
+
+	return item, err
+}
+
+// QueryAll builds and executes a statement and scans every row into a slice of T
+func QueryAll[T any](ctx context.Context, db DB, statement Statement) ([]T, error) {
+	var items []T
+	err := Execute(ctx, QueryOperation, statement, func(ctx context.Context, result Result) (int64, error) {
+		rows, err := db.QueryContext(ctx, result.Sql, result.Args...)
+		if err != nil {
+			return -1, WrapDBError(err)
+		}
+
+		items, err = ScanAll[T](rows)
+		if err != nil {
+			return -1, err
+		}
+
+		return int64(len(items)), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// rowScanner scans the rows of a result into values of a type
+type rowScanner struct {
+	// indexes are the field index of every result column, nil when the value is scanned as a whole
+	indexes [][]int
+}
+
+func newRowScanner(t reflect.Type, rows Rows) (rowScanner, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return rowScanner{}, WrapDBError(err)
+	}
+
+	if !isScannableStruct(t) {
+		if len(columns) != 1 {
+			return rowScanner{}, errortrace.
+				OnError(ErrUnknownResultColumn).
+				WithCode(errtype.InternalError).
+				WithMessage(fmt.Sprintf("cannot scan %d columns into a %s", len(columns), t))
+		}
+
+		return rowScanner{}, nil
+	}
+
+	indexByColumn := map[string][]int{}
+	for _, field := range columnFields(t) {
+		indexByColumn[field.column] = field.index
+	}
+
+	indexes := make([][]int, 0, len(columns))
+	for _, column := range columns {
+		index, ok := indexByColumn[column]
+		if !ok {
+			return rowScanner{}, errortrace.
+				OnError(ErrUnknownResultColumn).
+				WithCode(errtype.InternalError).
+				WithMessage(fmt.Sprintf("column %q has no matching field in %s", column, t))
+		}
+
+		indexes = append(indexes, index)
+	}
+
+	return rowScanner{indexes: indexes}, nil
+}
+
+func (r rowScanner) scan(rows Rows, item reflect.Value) error {
+	if r.indexes == nil {
+		dest := nullable(item)
+		if err := rows.Scan(dest); err != nil {
+			return WrapDBError(err)
+		}
+
+		setNullable(item, dest)
+
+		return nil
+	}
+
+	fields := make([]reflect.Value, 0, len(r.indexes))
+	dest := make([]any, 0, len(r.indexes))
+	for _, index := range r.indexes {
+		field, err := fieldByIndex(item, index)
+		if err != nil {
+			return err
+		}
+
+		fields = append(fields, field)
+		dest = append(dest, nullable(field))
+	}
+
+	if err := rows.Scan(dest...); err != nil {
+		return WrapDBError(err)
+	}
+
+	for i, field := range fields {
+		setNullable(field, dest[i])
+	}
+
+	return nil
+}
+
+// nullable returns the scan destination of a value, pointers and scanners handle NULL on their own,
+// any other value is scanned through a pointer to pointer, which database/sql sets to nil on NULL
+func nullable(value reflect.Value) any {
+	if value.Kind() == reflect.Pointer || value.Addr().Type().Implements(scannerType) {
+		return value.Addr().Interface()
+	}
+
+	return reflect.New(reflect.PointerTo(value.Type())).Interface()
+}
+
+// setNullable copies the value scanned by a pointer to pointer destination, NULL leaves the zero value
+func setNullable(value reflect.Value, dest any) {
+	if value.Kind() == reflect.Pointer || value.Addr().Type().Implements(scannerType) {
+		return
+	}
+
+	scanned := reflect.ValueOf(dest).Elem()
+	if scanned.IsNil() {
+		value.SetZero()
+		return
+	}
+
+	value.Set(scanned.Elem())
+}
+
+// fieldByIndex returns the nested field of a struct, allocating the nil embedded struct pointers on its way
+func fieldByIndex(value reflect.Value, index []int) (reflect.Value, error) {
+	for i, fieldIndex := range index {
+		if i > 0 && value.Kind() == reflect.Pointer {
+			if value.IsNil() {
+				if !value.CanSet() {
+					return reflect.Value{}, errortrace.
+						OnError(ErrInvalidStruct).
+						WithCode(errtype.InternalError).
+						WithMessage(fmt.Sprintf("cannot allocate the unexported embedded %s", value.Type()))
+				}
+
+				value.Set(reflect.New(value.Type().Elem()))
+			}
+
+			value = value.Elem()
+		}
+
+		value = value.Field(fieldIndex)
+	}
+
+	return value, nil
+}
+
+// isScannableStruct reports whether the columns are scanned into the fields of t
+// instead of into t itself, as for time.Time or a sql.Scanner
+func isScannableStruct(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t != timeType && !reflect.PointerTo(t).Implements(scannerType)
+}
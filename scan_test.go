@@ -0,0 +1,134 @@
+package sqlcraft
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/techforge-lat/errortrace/v2"
+)
+
+type scanAccount struct {
+	ID   int64  `db:"account_id"`
+	Plan string `db:"plan"`
+}
+
+type scanUser struct {
+	ID        int64           `db:"id"`
+	Name      string          `db:"name"`
+	Nickname  *string         `db:"nickname"`
+	Age       Optional[int64] `db:"age"`
+	CreatedAt time.Time       `db:"created_at"`
+	Ignored   string
+	scanAccount
+}
+
+func TestScanAll(t *testing.T) {
+	createdAt := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	nickname := "hernie"
+
+	tests := []struct {
+		name     string
+		database *fakeDatabase
+		want     []scanUser
+		wantErr  error
+	}{
+		{
+			name: "columns in any order with embedded struct",
+			database: &fakeDatabase{
+				columns: []string{"plan", "name", "id", "nickname", "age", "created_at", "account_id"},
+				rows: [][]driver.Value{
+					{"pro", "hernan", int64(1), "hernie", int64(30), createdAt, int64(7)},
+				},
+			},
+			want: []scanUser{
+				{ID: 1, Name: "hernan", Nickname: &nickname, Age: Some[int64](30), CreatedAt: createdAt, scanAccount: scanAccount{ID: 7, Plan: "pro"}},
+			},
+		},
+		{
+			name: "null placeholders leave zero values",
+			database: &fakeDatabase{
+				columns: []string{"id", "name", "nickname", "age", "created_at"},
+				rows: [][]driver.Value{
+					{int64(1), nil, nil, nil, nil},
+				},
+			},
+			want: []scanUser{{ID: 1, Age: Null[int64]()}},
+		},
+		{
+			name:     "no rows",
+			database: &fakeDatabase{columns: []string{"id"}},
+			want:     []scanUser{},
+		},
+		{
+			name:     "unknown column",
+			database: &fakeDatabase{columns: []string{"id", "email"}},
+			wantErr:  ErrUnknownResultColumn,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newFakeDB(t, tt.database)
+
+			got, err := QueryAll[scanUser](context.Background(), db, Result{Sql: "SELECT"})
+			if !errortrace.Is(err, tt.wantErr) {
+				t.Fatalf("QueryAll() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("QueryAll() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScanOne(t *testing.T) {
+	t.Run("first row", func(t *testing.T) {
+		db := newFakeDB(t, &fakeDatabase{
+			columns: []string{"id", "name"},
+			rows:    [][]driver.Value{{int64(1), "hernan"}, {int64(2), "maria"}},
+		})
+
+		got, err := QueryOne[scanUser](context.Background(), db, Select("id", "name").From("users"))
+		if err != nil {
+			t.Fatalf("QueryOne() error = %v", err)
+		}
+
+		if want := (scanUser{ID: 1, Name: "hernan"}); !reflect.DeepEqual(got, want) {
+			t.Errorf("QueryOne() = %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("no rows", func(t *testing.T) {
+		db := newFakeDB(t, &fakeDatabase{columns: []string{"id", "name"}})
+
+		_, err := QueryOne[scanUser](context.Background(), db, Select("id", "name").From("users"))
+		if !errortrace.Is(err, sql.ErrNoRows) {
+			t.Errorf("QueryOne() error = %v, want %v", err, sql.ErrNoRows)
+		}
+	})
+
+	t.Run("scalar", func(t *testing.T) {
+		db := newFakeDB(t, &fakeDatabase{columns: []string{"count"}, rows: [][]driver.Value{{int64(3)}}})
+
+		got, err := QueryOne[int](context.Background(), db, Result{Sql: "SELECT count(*) FROM users"})
+		if err != nil {
+			t.Fatalf("QueryOne() error = %v", err)
+		}
+
+		if got != 3 {
+			t.Errorf("QueryOne() = %v, want 3", got)
+		}
+	})
+
+	t.Run("scalar with many columns", func(t *testing.T) {
+		db := newFakeDB(t, &fakeDatabase{columns: []string{"id", "name"}, rows: [][]driver.Value{{int64(1), "hernan"}}})
+
+		_, err := QueryOne[int](context.Background(), db, Result{Sql: "SELECT id, name FROM users"})
+		if !errortrace.Is(err, ErrUnknownResultColumn) {
+			t.Errorf("QueryOne() error = %v, want %v", err, ErrUnknownResultColumn)
+		}
+	})
+}
@@ -1,7 +1,9 @@
 package sqlcraft
 
 import (
+	"context"
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -29,6 +31,10 @@ type SelectQuery struct {
 	columns                []string
 	requiredColumns        map[string]struct{}
 	sqlColumnByDomainField map[string]string
+	fieldPolicies          FieldPolicies
+	columnTypes            ColumnTypes
+	emptyIn                EmptyInBehavior
+	emptyNotIn             EmptyInBehavior
 
 	filters    dafi.Filters
 	sorts      dafi.Sorts
@@ -36,6 +42,12 @@ type SelectQuery struct {
 
 	groups []string
 	joins  []Join
+
+	softDeleteColumn string
+	deletedRows      deletedRows
+
+	ctx     context.Context
+	tenancy tenancy
 }
 
 func Select(columns ...string) SelectQuery {
@@ -91,6 +103,84 @@ func (s SelectQuery) SQLColumnByDomainField(sqlColumnByDomainField map[string]st
 	return s
 }
 
+// FieldPolicies restricts the domain fields a client can filter, sort and group by
+func (s SelectQuery) FieldPolicies(fieldPolicies FieldPolicies) SelectQuery {
+	s.fieldPolicies = fieldPolicies
+
+	return s
+}
+
+// ColumnTypes coerces the string filter values into the type of their sql column
+func (s SelectQuery) ColumnTypes(columnTypes ColumnTypes) SelectQuery {
+	s.columnTypes = columnTypes
+
+	return s
+}
+
+// OnEmptyIn defines how an IN filter with an empty list is built
+func (s SelectQuery) OnEmptyIn(behavior EmptyInBehavior) SelectQuery {
+	s.emptyIn = behavior
+
+	return s
+}
+
+// OnEmptyNotIn defines how a NOT IN filter with an empty list is built
+func (s SelectQuery) OnEmptyNotIn(behavior EmptyInBehavior) SelectQuery {
+	s.emptyNotIn = behavior
+
+	return s
+}
+
+func (s SelectQuery) GroupBy(groups ...string) SelectQuery {
+	s.groups = groups
+
+	return s
+}
+
+// SoftDelete marks the table as soft-deleted by the given column,
+// rows with a non-null column are excluded unless WithDeleted or OnlyDeleted are used
+func (s SelectQuery) SoftDelete(column string) SelectQuery {
+	s.softDeleteColumn = column
+
+	return s
+}
+
+// WithDeleted includes the soft-deleted rows
+func (s SelectQuery) WithDeleted() SelectQuery {
+	s.deletedRows = includeDeletedRows
+
+	return s
+}
+
+// OnlyDeleted selects only the soft-deleted rows
+func (s SelectQuery) OnlyDeleted() SelectQuery {
+	s.deletedRows = onlyDeletedRows
+
+	return s
+}
+
+// WithContext sets the context used to resolve the tenant
+func (s SelectQuery) WithContext(ctx context.Context) SelectQuery {
+	s.ctx = ctx
+
+	return s
+}
+
+// WithTenantScope scopes the query to the tenant from ForTenant or the query context
+func (s SelectQuery) WithTenantScope(scope TenantScope) SelectQuery {
+	s.tenancy.scope = &scope
+
+	return s
+}
+
+// ForTenant sets the tenant of a query built WithTenantScope, instead of taking it from the context
+func (s SelectQuery) ForTenant(tenant any) SelectQuery {
+	s.tenancy.tenant = tenant
+	s.tenancy.isSet = true
+
+	return s
+}
+
 func (s SelectQuery) InnerJoin(table, condition string) SelectQuery {
 	return s.addJoin(InnerJoinType, table, condition)
 }
@@ -166,19 +256,21 @@ func (s SelectQuery) ToSQL() (Result, error) {
 		builder.WriteString(join.Condition)
 	}
 
-	args := []any{}
-	if len(s.filters) > 0 {
-		whereResult, err := WhereSafe(0, s.sqlColumnByDomainField, s.filters...)
-		if err != nil {
-			return Result{}, err
-		}
-		args = append(args, whereResult.Args...)
-
-		builder.WriteString(whereResult.Sql)
+	whereResult, err := WhereWithOptions(0, s.whereOptions(), s.filters...)
+	if err != nil {
+		return Result{}, err
 	}
+	scopes, err := s.scopes()
+	if err != nil {
+		return Result{}, err
+	}
+	whereResult = andScopes(whereResult, 0, scopes...)
+
+	args := append([]any{}, whereResult.Args...)
+	builder.WriteString(whereResult.Sql)
 
 	if len(s.groups) > 0 {
-		groupSQL, err := BuildGroupBy(s.groups, s.sqlColumnByDomainField)
+		groupSQL, err := s.buildGroupBy()
 		if err != nil {
 			return Result{}, err
 		}
@@ -187,7 +279,10 @@ func (s SelectQuery) ToSQL() (Result, error) {
 	}
 
 	if len(s.sorts) > 0 {
-		sortSql := BuildOrderBy(s.sorts)
+		sortSql, err := s.buildOrderBy()
+		if err != nil {
+			return Result{}, err
+		}
 
 		builder.WriteString(sortSql)
 	}
@@ -201,15 +296,70 @@ func (s SelectQuery) ToSQL() (Result, error) {
 	}, nil
 }
 
+// scopes returns the predicates added to the filters
+func (s SelectQuery) scopes() ([]Expr, error) {
+	tenantScopes, err := s.tenancy.scopes(s.ctx, s.table)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(tenantScopes, softDeleteScopes(qualifiedColumn(s.table, s.softDeleteColumn), s.deletedRows)...), nil
+}
+
+func (s SelectQuery) whereOptions() WhereOptions {
+	return WhereOptions{
+		SQLColumnByDomainField: s.sqlColumnByDomainField,
+		FieldPolicies:          s.fieldPolicies,
+		ColumnTypes:            s.columnTypes,
+		EmptyIn:                s.emptyIn,
+		EmptyNotIn:             s.emptyNotIn,
+	}
+}
+
+func (s SelectQuery) buildOrderBy() (string, error) {
+	if len(s.fieldPolicies) > 0 {
+		return BuildOrderByWithPolicies(s.sorts, s.fieldPolicies)
+	}
+
+	return BuildOrderBy(s.sorts), nil
+}
+
+func (s SelectQuery) buildGroupBy() (string, error) {
+	if len(s.fieldPolicies) > 0 {
+		return BuildGroupByWithPolicies(s.groups, s.fieldPolicies)
+	}
+
+	return BuildGroupBy(s.groups, s.sqlColumnByDomainField)
+}
+
 func BuildOrderBy(sorts dafi.Sorts) string {
+	sortSQL, _ := buildOrderBy(sorts, func(field string) (string, error) {
+		return field, nil
+	})
+
+	return sortSQL
+}
+
+// BuildOrderByWithPolicies returns an ORDER BY sql sentence with the sql columns of the sorted domain fields,
+// if a domain field is not sortable it will return an error
+func BuildOrderByWithPolicies(sorts dafi.Sorts, fieldPolicies FieldPolicies) (string, error) {
+	return buildOrderBy(sorts, fieldPolicies.SortColumn)
+}
+
+func buildOrderBy(sorts dafi.Sorts, sqlColumn func(field string) (string, error)) (string, error) {
 	if sorts.IsZero() {
-		return ""
+		return "", nil
 	}
 
 	builder := strings.Builder{}
 	builder.WriteString(" ORDER BY ")
 	for i, sort := range sorts {
-		builder.WriteString(string(sort.Field))
+		column, err := sqlColumn(string(sort.Field))
+		if err != nil {
+			return "", err
+		}
+
+		builder.WriteString(column)
 
 		if sort.Type != dafi.None {
 			builder.WriteString(" ")
@@ -221,7 +371,7 @@ func BuildOrderBy(sorts dafi.Sorts) string {
 		}
 	}
 
-	return builder.String()
+	return builder.String(), nil
 }
 
 func BuildPagination(pagination dafi.Pagination) string {
@@ -247,6 +397,7 @@ func BuildPagination(pagination dafi.Pagination) string {
 
 func BuildGroupBy(groups []string, sqlColumnByDomainField map[string]string) (string, error) {
 	if len(sqlColumnByDomainField) > 0 {
+		groups = slices.Clone(groups)
 		for i, group := range groups {
 			sqlColumnName, ok := sqlColumnByDomainField[group]
 			if !ok {
@@ -259,3 +410,19 @@ func BuildGroupBy(groups []string, sqlColumnByDomainField map[string]string) (st
 
 	return " GROUP BY " + strings.Join(groups, ", "), nil
 }
+
+// BuildGroupByWithPolicies returns a GROUP BY sql sentence with the sql columns of the grouped domain fields,
+// if a domain field is not groupable it will return an error
+func BuildGroupByWithPolicies(groups []string, fieldPolicies FieldPolicies) (string, error) {
+	columns := make([]string, 0, len(groups))
+	for _, group := range groups {
+		column, err := fieldPolicies.GroupColumn(group)
+		if err != nil {
+			return "", err
+		}
+
+		columns = append(columns, column)
+	}
+
+	return " GROUP BY " + strings.Join(columns, ", "), nil
+}
@@ -0,0 +1,26 @@
+package sqlcraft
+
+// deletedRows defines which rows a query over a soft-deleted table can see
+type deletedRows uint8
+
+const (
+	excludeDeletedRows deletedRows = iota
+	includeDeletedRows
+	onlyDeletedRows
+)
+
+// softDeleteScopes returns the predicate that hides (or shows only) the soft-deleted rows
+func softDeleteScopes(column string, rows deletedRows) []Expr {
+	if column == "" {
+		return nil
+	}
+
+	switch rows {
+	case includeDeletedRows:
+		return nil
+	case onlyDeletedRows:
+		return []Expr{Raw(column + " IS NOT NULL")}
+	default:
+		return []Expr{Raw(column + " IS NULL")}
+	}
+}
@@ -0,0 +1,94 @@
+package sqlcraft
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/techforge-lat/dafi/v2"
+)
+
+func TestSoftDelete(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   interface{ ToSQL() (Result, error) }
+		want    Result
+		wantErr bool
+	}{
+		{
+			name:  "soft delete",
+			query: DeleteFrom("users").SoftDelete("deleted_at").Where(dafi.Filter{Field: "id", Value: 1}).Returning("id"),
+			want: Result{
+				Sql:  "UPDATE users SET deleted_at = now() WHERE (id = $1) AND users.deleted_at IS NULL RETURNING id",
+				Args: []any{1},
+			},
+		},
+		{
+			name:    "soft delete without filters",
+			query:   DeleteFrom("users").SoftDelete("deleted_at"),
+			wantErr: true,
+		},
+		{
+			name:  "select excludes deleted rows",
+			query: Select("id", "email").From("users").SoftDelete("deleted_at"),
+			want: Result{
+				Sql:  "SELECT id, email FROM users WHERE users.deleted_at IS NULL",
+				Args: []any{},
+			},
+		},
+		{
+			name:  "select with deleted rows",
+			query: Select("id", "email").From("users").SoftDelete("deleted_at").WithDeleted().Where(dafi.Filter{Field: "email", Value: "hernan_rm@outlook.es"}),
+			want: Result{
+				Sql:  "SELECT id, email FROM users WHERE email = $1",
+				Args: []any{"hernan_rm@outlook.es"},
+			},
+		},
+		{
+			name:  "select only deleted rows",
+			query: Select("id", "email").From("users").SoftDelete("users.deleted_at").OnlyDeleted().Where(dafi.Filter{Field: "email", Value: "hernan_rm@outlook.es"}).Limit(10),
+			want: Result{
+				Sql:  "SELECT id, email FROM users WHERE (email = $1) AND users.deleted_at IS NOT NULL LIMIT 10 OFFSET 0",
+				Args: []any{"hernan_rm@outlook.es"},
+			},
+		},
+		{
+			name:  "select with join qualifies the soft delete column",
+			query: Select("u.id", "o.id").From("users u").InnerJoin("orders o", "o.user_id = u.id").SoftDelete("deleted_at"),
+			want: Result{
+				Sql:  "SELECT u.id, o.id FROM users u INNER JOIN orders o ON o.user_id = u.id WHERE u.deleted_at IS NULL",
+				Args: []any{},
+			},
+		},
+		{
+			name:  "update excludes deleted rows",
+			query: Update("users").Set("email", "brownie@gmail.com").SoftDelete("deleted_at").Where(dafi.Filter{Field: "id", Value: 1}),
+			want: Result{
+				Sql:  "UPDATE users SET email = $1 WHERE (id = $2) AND users.deleted_at IS NULL",
+				Args: []any{"brownie@gmail.com", 1},
+			},
+		},
+		{
+			name:  "restore deleted rows",
+			query: Update("users").Set("deleted_at", nil).SoftDelete("deleted_at").OnlyDeleted().Where(dafi.Filter{Field: "id", Value: 1}),
+			want: Result{
+				Sql:  "UPDATE users SET deleted_at = $1 WHERE (id = $2) AND users.deleted_at IS NOT NULL",
+				Args: []any{nil, 1},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.query.ToSQL()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ToSQL() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ToSQL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
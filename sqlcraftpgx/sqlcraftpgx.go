@@ -0,0 +1,336 @@
+// Package sqlcraftpgx executes sqlcraft statements with pgx, without going through database/sql
+package sqlcraftpgx
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/techforge-lat/sqlcraft"
+)
+
+// DB executes sql statements, it is implemented by *pgx.Conn, *pgxpool.Pool, *pgxpool.Conn and pgx.Tx
+type DB interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	SendBatch(ctx context.Context, batch *pgx.Batch) pgx.BatchResults
+	CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error)
+}
+
+// Exec builds and executes a statement that returns no rows, with the hooks of the context
+func Exec(ctx context.Context, db DB, statement sqlcraft.Statement) (pgconn.CommandTag, error) {
+	var tag pgconn.CommandTag
+	err := sqlcraft.Execute(ctx, sqlcraft.ExecOperation, statement, func(ctx context.Context, result sqlcraft.Result) (int64, error) {
+		var err error
+		tag, err = db.Exec(ctx, result.Sql, result.Args...)
+		if err != nil {
+			return -1, sqlcraft.WrapDBError(err)
+		}
+
+		return tag.RowsAffected(), nil
+	})
+	if err != nil {
+		return pgconn.CommandTag{}, err
+	}
+
+	return tag, nil
+}
+
+// Query builds and executes a statement that returns rows, with the hooks of the context,
+// the caller must close them
+func Query(ctx context.Context, db DB, statement sqlcraft.Statement) (pgx.Rows, error) {
+	var rows pgx.Rows
+	err := sqlcraft.Execute(ctx, sqlcraft.QueryOperation, statement, func(ctx context.Context, result sqlcraft.Result) (int64, error) {
+		var err error
+		rows, err = db.Query(ctx, result.Sql, result.Args...)
+
+		return -1, sqlcraft.WrapDBError(err)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// QueryRow builds and executes a statement that returns at most one row and scans it into dest,
+// if there are no rows it returns a not found error
+func QueryRow(ctx context.Context, db DB, statement sqlcraft.Statement, dest ...any) error {
+	return sqlcraft.Execute(ctx, sqlcraft.QueryRowOperation, statement, func(ctx context.Context, result sqlcraft.Result) (int64, error) {
+		err := db.QueryRow(ctx, result.Sql, result.Args...).Scan(dest...)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, sqlcraft.WrapDBError(err)
+		}
+
+		if err != nil {
+			return -1, sqlcraft.WrapDBError(err)
+		}
+
+		return 1, nil
+	})
+}
+
+// QueryOne builds and executes a statement and scans its first row into a T, see sqlcraft.ScanOne
+func QueryOne[T any](ctx context.Context, db DB, statement sqlcraft.Statement) (T, error) {
+	var item T
+	err := sqlcraft.Execute(ctx, sqlcraft.QueryOperation, statement, func(ctx context.Context, result sqlcraft.Result) (int64, error) {
+		rows, err := db.Query(ctx, result.Sql, result.Args...)
+		if err != nil {
+			return -1, sqlcraft.WrapDBError(err)
+		}
+
+		item, err = sqlcraft.ScanOne[T](Rows(rows))
+		if err != nil {
+			return 0, err
+		}
+
+		return 1, nil
+	})
+
+	return item, err
+}
+
+// QueryAll builds and executes a statement and scans every row into a slice of T, see sqlcraft.ScanAll
+func QueryAll[T any](ctx context.Context, db DB, statement sqlcraft.Statement) ([]T, error) {
+	var items []T
+	err := sqlcraft.Execute(ctx, sqlcraft.QueryOperation, statement, func(ctx context.Context, result sqlcraft.Result) (int64, error) {
+		rows, err := db.Query(ctx, result.Sql, result.Args...)
+		if err != nil {
+			return -1, sqlcraft.WrapDBError(err)
+		}
+
+		items, err = sqlcraft.ScanAll[T](Rows(rows))
+		if err != nil {
+			return -1, err
+		}
+
+		return int64(len(items)), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// SendBatch builds the statements and sends them in a single round trip, with the hooks of the context,
+// the caller must read the results in the same order and close them.
+// Reading a result runs the query hooks of its statement and wraps its error with sqlcraft.WrapDBError
+func SendBatch(ctx context.Context, db DB, statements ...sqlcraft.Statement) (pgx.BatchResults, error) {
+	batch := &pgx.Batch{}
+	results := make([]sqlcraft.Result, 0, len(statements))
+	for _, statement := range statements {
+		result, err := sqlcraft.Build(ctx, statement)
+		if err != nil {
+			return nil, err
+		}
+
+		batch.Queue(result.Sql, result.Args...)
+		results = append(results, result)
+	}
+
+	return &batchResults{ctx: ctx, results: results, batchResults: db.SendBatch(ctx, batch)}, nil
+}
+
+// ExecBatch sends the statements in a single round trip and returns the command tag of each of them
+func ExecBatch(ctx context.Context, db DB, statements ...sqlcraft.Statement) ([]pgconn.CommandTag, error) {
+	results, err := SendBatch(ctx, db, statements...)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make([]pgconn.CommandTag, 0, len(statements))
+	for range statements {
+		tag, err := results.Exec()
+		if err != nil {
+			results.Close()
+
+			return nil, err
+		}
+
+		tags = append(tags, tag)
+	}
+
+	if err := results.Close(); err != nil {
+		return nil, sqlcraft.WrapDBError(err)
+	}
+
+	return tags, nil
+}
+
+// CopyFrom inserts the rows of the query with the COPY protocol, which is much faster than
+// a multi-row INSERT for large payloads, its returning columns are ignored.
+// The query hooks get a COPY statement without the rows as args
+func CopyFrom(ctx context.Context, db DB, query sqlcraft.InsertQuery) (int64, error) {
+	data, err := query.ToCopyData()
+	if err != nil {
+		return 0, err
+	}
+
+	result := sqlcraft.Result{Sql: "COPY " + data.Table + " (" + strings.Join(data.Columns, ", ") + ") FROM STDIN"}
+
+	var count int64
+	err = sqlcraft.Run(ctx, sqlcraft.CopyOperation, result, func(ctx context.Context, _ sqlcraft.Result) (int64, error) {
+		var err error
+		count, err = db.CopyFrom(ctx, pgx.Identifier(strings.Split(data.Table, ".")), data.Columns, pgx.CopyFromRows(data.Rows))
+		if err != nil {
+			return -1, sqlcraft.WrapDBError(err)
+		}
+
+		return count, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// batchResults runs the query hooks of every statement of a batch when its result is read
+type batchResults struct {
+	ctx          context.Context
+	results      []sqlcraft.Result
+	next         int
+	batchResults pgx.BatchResults
+}
+
+// result returns the statement of the next result, false when the batch has no more statements
+func (b *batchResults) result() (sqlcraft.Result, bool) {
+	if b.next >= len(b.results) {
+		return sqlcraft.Result{}, false
+	}
+	b.next++
+
+	return b.results[b.next-1], true
+}
+
+func (b *batchResults) Exec() (pgconn.CommandTag, error) {
+	result, ok := b.result()
+	if !ok {
+		return b.batchResults.Exec()
+	}
+
+	var tag pgconn.CommandTag
+	err := sqlcraft.Run(b.ctx, sqlcraft.ExecOperation, result, func(context.Context, sqlcraft.Result) (int64, error) {
+		var err error
+		tag, err = b.batchResults.Exec()
+		if err != nil {
+			return -1, sqlcraft.WrapDBError(err)
+		}
+
+		return tag.RowsAffected(), nil
+	})
+
+	return tag, err
+}
+
+func (b *batchResults) Query() (pgx.Rows, error) {
+	result, ok := b.result()
+	if !ok {
+		return b.batchResults.Query()
+	}
+
+	var rows pgx.Rows
+	err := sqlcraft.Run(b.ctx, sqlcraft.QueryOperation, result, func(context.Context, sqlcraft.Result) (int64, error) {
+		var err error
+		rows, err = b.batchResults.Query()
+
+		return -1, sqlcraft.WrapDBError(err)
+	})
+
+	return rows, err
+}
+
+func (b *batchResults) QueryRow() pgx.Row {
+	result, ok := b.result()
+	if !ok {
+		return b.batchResults.QueryRow()
+	}
+
+	return batchRow{ctx: b.ctx, result: result, row: b.batchResults.QueryRow()}
+}
+
+func (b *batchResults) Close() error {
+	return b.batchResults.Close()
+}
+
+// batchRow runs the query hooks of its statement when it is scanned, since pgx reads the row then
+type batchRow struct {
+	ctx    context.Context
+	result sqlcraft.Result
+	row    pgx.Row
+}
+
+func (r batchRow) Scan(dest ...any) error {
+	return sqlcraft.Run(r.ctx, sqlcraft.QueryRowOperation, r.result, func(context.Context, sqlcraft.Result) (int64, error) {
+		err := r.row.Scan(dest...)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, sqlcraft.WrapDBError(err)
+		}
+
+		if err != nil {
+			return -1, sqlcraft.WrapDBError(err)
+		}
+
+		return 1, nil
+	})
+}
+
+// CheckVersion returns a conflict error when an UpdateQuery built WithVersion did not affect any row,
+// see sqlcraft.CheckVersion
+func CheckVersion(tag pgconn.CommandTag) error {
+	return sqlcraft.CheckVersion(commandTagResult{tag: tag})
+}
+
+// commandTagResult adapts a command tag to sql.Result
+type commandTagResult struct {
+	tag pgconn.CommandTag
+}
+
+func (r commandTagResult) LastInsertId() (int64, error) {
+	return 0, errors.New("LastInsertId is not supported by pgx")
+}
+
+func (r commandTagResult) RowsAffected() (int64, error) {
+	return r.tag.RowsAffected(), nil
+}
+
+// Rows adapts pgx rows to sqlcraft.Rows so they can be scanned with sqlcraft.ScanOne and sqlcraft.ScanAll
+func Rows(rows pgx.Rows) sqlcraft.Rows {
+	return pgxRows{rows: rows}
+}
+
+type pgxRows struct {
+	rows pgx.Rows
+}
+
+func (r pgxRows) Columns() ([]string, error) {
+	fields := r.rows.FieldDescriptions()
+	columns := make([]string, 0, len(fields))
+	for _, field := range fields {
+		columns = append(columns, field.Name)
+	}
+
+	return columns, nil
+}
+
+func (r pgxRows) Next() bool {
+	return r.rows.Next()
+}
+
+func (r pgxRows) Scan(dest ...any) error {
+	return r.rows.Scan(dest...)
+}
+
+func (r pgxRows) Err() error {
+	return r.rows.Err()
+}
+
+func (r pgxRows) Close() error {
+	r.rows.Close()
+
+	return r.rows.Err()
+}
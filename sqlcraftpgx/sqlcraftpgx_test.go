@@ -0,0 +1,395 @@
+package sqlcraftpgx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/techforge-lat/dafi/v2"
+	"github.com/techforge-lat/errortrace/v2"
+	"github.com/techforge-lat/sqlcraft"
+)
+
+// fakeDB records the executed statements and answers every query with the same rows
+type fakeDB struct {
+	statements []sqlcraft.Result
+	copies     []copyCall
+	columns    []string
+	rows       [][]any
+	err        error
+}
+
+type copyCall struct {
+	table   pgx.Identifier
+	columns []string
+	rows    [][]any
+}
+
+func (f *fakeDB) record(sql string, args []any) {
+	f.statements = append(f.statements, sqlcraft.Result{Sql: sql, Args: args})
+}
+
+func (f *fakeDB) Exec(_ context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	f.record(sql, args)
+	if f.err != nil {
+		return pgconn.CommandTag{}, f.err
+	}
+
+	return pgconn.NewCommandTag("UPDATE 1"), nil
+}
+
+func (f *fakeDB) Query(_ context.Context, sql string, args ...any) (pgx.Rows, error) {
+	f.record(sql, args)
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	return &fakeRows{columns: f.columns, rows: f.rows}, nil
+}
+
+func (f *fakeDB) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	rows, err := f.Query(ctx, sql, args...)
+	if err != nil {
+		return fakeRow{err: err}
+	}
+
+	return fakeRow{rows: rows}
+}
+
+func (f *fakeDB) SendBatch(_ context.Context, batch *pgx.Batch) pgx.BatchResults {
+	for _, query := range batch.QueuedQueries {
+		f.record(query.SQL, query.Arguments)
+	}
+
+	return &fakeBatchResults{db: f, pending: batch.Len()}
+}
+
+func (f *fakeDB) CopyFrom(_ context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error) {
+	var rows [][]any
+	for rowSrc.Next() {
+		values, err := rowSrc.Values()
+		if err != nil {
+			return 0, err
+		}
+
+		rows = append(rows, values)
+	}
+
+	f.copies = append(f.copies, copyCall{table: tableName, columns: columnNames, rows: rows})
+
+	return int64(len(rows)), nil
+}
+
+type fakeRow struct {
+	rows pgx.Rows
+	err  error
+}
+
+func (r fakeRow) Scan(dest ...any) error {
+	if r.err != nil {
+		return r.err
+	}
+	defer r.rows.Close()
+
+	if !r.rows.Next() {
+		return pgx.ErrNoRows
+	}
+
+	return r.rows.Scan(dest...)
+}
+
+type fakeBatchResults struct {
+	db      *fakeDB
+	pending int
+}
+
+func (b *fakeBatchResults) Exec() (pgconn.CommandTag, error) {
+	if b.pending == 0 {
+		return pgconn.CommandTag{}, errors.New("no more results")
+	}
+	b.pending--
+
+	if b.db.err != nil {
+		return pgconn.CommandTag{}, b.db.err
+	}
+
+	return pgconn.NewCommandTag("INSERT 0 1"), nil
+}
+
+func (b *fakeBatchResults) Query() (pgx.Rows, error) {
+	b.pending--
+
+	return &fakeRows{columns: b.db.columns, rows: b.db.rows}, b.db.err
+}
+
+func (b *fakeBatchResults) QueryRow() pgx.Row {
+	rows, err := b.Query()
+
+	return fakeRow{rows: rows, err: err}
+}
+
+func (b *fakeBatchResults) Close() error {
+	return nil
+}
+
+type fakeRows struct {
+	columns []string
+	rows    [][]any
+	next    int
+}
+
+func (r *fakeRows) Close()                        {}
+func (r *fakeRows) Err() error                    { return nil }
+func (r *fakeRows) CommandTag() pgconn.CommandTag { return pgconn.NewCommandTag("SELECT") }
+func (r *fakeRows) RawValues() [][]byte           { return nil }
+func (r *fakeRows) Conn() *pgx.Conn               { return nil }
+
+func (r *fakeRows) FieldDescriptions() []pgconn.FieldDescription {
+	fields := make([]pgconn.FieldDescription, 0, len(r.columns))
+	for _, column := range r.columns {
+		fields = append(fields, pgconn.FieldDescription{Name: column})
+	}
+
+	return fields
+}
+
+func (r *fakeRows) Next() bool {
+	if r.next >= len(r.rows) {
+		return false
+	}
+	r.next++
+
+	return true
+}
+
+func (r *fakeRows) Values() ([]any, error) {
+	return r.rows[r.next-1], nil
+}
+
+// Scan assigns the values like pgx does for the destinations used by sqlcraft: pointers, pointers to pointers and scanners
+func (r *fakeRows) Scan(dest ...any) error {
+	for i, value := range r.rows[r.next-1] {
+		if scanner, ok := dest[i].(sql.Scanner); ok {
+			if err := scanner.Scan(value); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		target := reflect.ValueOf(dest[i]).Elem()
+		if value == nil {
+			target.SetZero()
+			continue
+		}
+
+		if target.Kind() == reflect.Pointer {
+			target.Set(reflect.New(target.Type().Elem()))
+			target = target.Elem()
+		}
+
+		target.Set(reflect.ValueOf(value).Convert(target.Type()))
+	}
+
+	return nil
+}
+
+type user struct {
+	ID       int64                     `db:"id"`
+	Name     string                    `db:"name"`
+	Nickname sqlcraft.Optional[string] `db:"nickname"`
+}
+
+func TestExec(t *testing.T) {
+	db := &fakeDB{}
+
+	tag, err := Exec(context.Background(), db, sqlcraft.Update("users").Set("name", "hernan").Where(dafi.Filter{Field: "id", Value: 1}))
+	if err != nil {
+		t.Fatalf("Exec() error = %v", err)
+	}
+
+	if tag.RowsAffected() != 1 {
+		t.Errorf("Exec() rows affected = %v, want 1", tag.RowsAffected())
+	}
+
+	want := []sqlcraft.Result{{Sql: "UPDATE users SET name = $1 WHERE id = $2", Args: []any{"hernan", 1}}}
+	if !reflect.DeepEqual(db.statements, want) {
+		t.Errorf("Exec() executed = %v, want %v", db.statements, want)
+	}
+}
+
+func TestExec_DriverError(t *testing.T) {
+	pgErr := &pgconn.PgError{Code: "23505"}
+	db := &fakeDB{err: pgErr}
+
+	_, err := Exec(context.Background(), db, sqlcraft.Result{Sql: "INSERT INTO users (id) VALUES (1)"})
+	if !errortrace.Is(err, pgErr) {
+		t.Fatalf("Exec() error = %v, want %v", err, pgErr)
+	}
+
+	var errTrace *errortrace.Error
+	if !errors.As(err, &errTrace) || errTrace.Code != string(sqlcraft.CodeConflict) {
+		t.Errorf("Exec() error = %v, want code %v", err, sqlcraft.CodeConflict)
+	}
+}
+
+func TestCheckVersion(t *testing.T) {
+	if err := CheckVersion(pgconn.NewCommandTag("UPDATE 1")); err != nil {
+		t.Errorf("CheckVersion() error = %v, want nil", err)
+	}
+
+	if err := CheckVersion(pgconn.NewCommandTag("UPDATE 0")); !errortrace.Is(err, sqlcraft.ErrVersionConflict) {
+		t.Errorf("CheckVersion() error = %v, want %v", err, sqlcraft.ErrVersionConflict)
+	}
+}
+
+func TestQueryAll(t *testing.T) {
+	db := &fakeDB{
+		columns: []string{"id", "name", "nickname"},
+		rows:    [][]any{{int64(1), "hernan", "hernie"}, {int64(2), nil, nil}},
+	}
+
+	got, err := QueryAll[user](context.Background(), db, sqlcraft.Select("id", "name", "nickname").From("users"))
+	if err != nil {
+		t.Fatalf("QueryAll() error = %v", err)
+	}
+
+	want := []user{
+		{ID: 1, Name: "hernan", Nickname: sqlcraft.Some("hernie")},
+		{ID: 2, Nickname: sqlcraft.Null[string]()},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("QueryAll() = %+v, want %+v", got, want)
+	}
+}
+
+func TestQueryRow_NoRows(t *testing.T) {
+	db := &fakeDB{columns: []string{"name"}}
+
+	var name string
+	err := QueryRow(context.Background(), db, sqlcraft.Select("name").From("users"), &name)
+	if !errortrace.Is(err, sql.ErrNoRows) {
+		t.Errorf("QueryRow() error = %v, want %v", err, sql.ErrNoRows)
+	}
+}
+
+func TestExecBatch(t *testing.T) {
+	db := &fakeDB{}
+
+	tags, err := ExecBatch(context.Background(), db,
+		sqlcraft.InsertInto("users").WithColumns("name").WithValues("hernan"),
+		sqlcraft.DeleteFrom("sessions").Where(dafi.Filter{Field: "user_id", Value: 1}),
+	)
+	if err != nil {
+		t.Fatalf("ExecBatch() error = %v", err)
+	}
+
+	if len(tags) != 2 {
+		t.Errorf("ExecBatch() tags = %v, want 2", len(tags))
+	}
+
+	want := []sqlcraft.Result{
+		{Sql: "INSERT INTO users (name) VALUES ($1)", Args: []any{"hernan"}},
+		{Sql: "DELETE FROM sessions WHERE user_id = $1", Args: []any{1}},
+	}
+	if !reflect.DeepEqual(db.statements, want) {
+		t.Errorf("ExecBatch() executed = %v, want %v", db.statements, want)
+	}
+}
+
+// recordingHook records the built and executed statements
+type recordingHook struct {
+	sqlcraft.NopHook
+	events *[]string
+}
+
+func (h recordingHook) AfterBuild(_ context.Context, event sqlcraft.BuildEvent) {
+	*h.events = append(*h.events, "build "+event.Result.Sql)
+}
+
+func (h recordingHook) AfterQuery(_ context.Context, event sqlcraft.QueryEvent) {
+	*h.events = append(*h.events, string(event.Operation)+" "+event.Result.Sql+" rows="+strconv.FormatInt(event.RowsAffected, 10))
+}
+
+func TestBatchAndCopy_Hooks(t *testing.T) {
+	db := &fakeDB{columns: []string{"id"}, rows: [][]any{{int64(7)}}}
+	var events []string
+	ctx := sqlcraft.WithHooks(context.Background(), recordingHook{events: &events})
+
+	if _, err := ExecBatch(ctx, db, sqlcraft.InsertInto("users").WithColumns("name").WithValues("hernan")); err != nil {
+		t.Fatalf("ExecBatch() error = %v", err)
+	}
+
+	results, err := SendBatch(ctx, db, sqlcraft.Select("id").From("users"))
+	if err != nil {
+		t.Fatalf("SendBatch() error = %v", err)
+	}
+
+	var id int64
+	if err := results.QueryRow().Scan(&id); err != nil {
+		t.Fatalf("QueryRow().Scan() error = %v", err)
+	}
+	results.Close()
+
+	if _, err := CopyFrom(ctx, db, sqlcraft.InsertInto("users").WithColumns("name").WithValues("hernan")); err != nil {
+		t.Fatalf("CopyFrom() error = %v", err)
+	}
+
+	want := []string{
+		"build INSERT INTO users (name) VALUES ($1)",
+		"exec INSERT INTO users (name) VALUES ($1) rows=1",
+		"build SELECT id FROM users",
+		"query_row SELECT id FROM users rows=1",
+		"copy COPY users (name) FROM STDIN rows=1",
+	}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("hook events = %q, want %q", events, want)
+	}
+}
+
+func TestSendBatch_BuildError(t *testing.T) {
+	db := &fakeDB{}
+
+	_, err := SendBatch(context.Background(), db, sqlcraft.Result{Sql: "SELECT 1"}, sqlcraft.DeleteFrom("users"))
+	if !errors.Is(err, sqlcraft.ErrMissingFilters) {
+		t.Errorf("SendBatch() error = %v, want %v", err, sqlcraft.ErrMissingFilters)
+	}
+
+	if len(db.statements) != 0 {
+		t.Errorf("SendBatch() executed = %v, want nothing", db.statements)
+	}
+}
+
+func TestCopyFrom(t *testing.T) {
+	db := &fakeDB{}
+
+	query := sqlcraft.InsertInto("public.users").
+		WithColumns("name", "email").
+		WithValues("hernan", "hernan_rm@outlook.es").
+		WithValues("brownie", "brownie@gmail.com")
+
+	count, err := CopyFrom(context.Background(), db, query)
+	if err != nil {
+		t.Fatalf("CopyFrom() error = %v", err)
+	}
+
+	if count != 2 {
+		t.Errorf("CopyFrom() = %v, want 2", count)
+	}
+
+	want := []copyCall{
+		{
+			table:   pgx.Identifier{"public", "users"},
+			columns: []string{"name", "email"},
+			rows:    [][]any{{"hernan", "hernan_rm@outlook.es"}, {"brownie", "brownie@gmail.com"}},
+		},
+	}
+	if !reflect.DeepEqual(db.copies, want) {
+		t.Errorf("CopyFrom() copies = %v, want %v", db.copies, want)
+	}
+}
@@ -0,0 +1,129 @@
+package sqlcraft
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// columnField is a struct field tagged with a sql column
+type columnField struct {
+	column string
+	index  []int
+	// primaryKey is set by the pk option of the tag, e.g. `db:"id,pk"`
+	primaryKey bool
+}
+
+// columnFieldsByType caches the columnFields of every struct type
+var columnFieldsByType sync.Map
+
+// columnFields returns the fields of a struct type tagged with `db`, including the fields of its embedded structs,
+// when two fields share a column the less nested one wins
+func columnFields(t reflect.Type) []columnField {
+	if cached, ok := columnFieldsByType.Load(t); ok {
+		return cached.([]columnField)
+	}
+
+	fields := appendColumnFields(nil, t, nil, map[string]struct{}{})
+	columnFieldsByType.Store(t, fields)
+
+	return fields
+}
+
+func appendColumnFields(fields []columnField, t reflect.Type, parentIndex []int, seen map[string]struct{}) []columnField {
+	var embedded []reflect.StructField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		column, ok := columnName(field)
+
+		if !ok && field.Anonymous && field.Tag.Get("db") != "-" {
+			embedded = append(embedded, field)
+			continue
+		}
+
+		if !ok || !field.IsExported() {
+			continue
+		}
+
+		if _, exists := seen[column]; exists {
+			continue
+		}
+		seen[column] = struct{}{}
+
+		fields = append(fields, columnField{
+			column:     column,
+			index:      append(append([]int{}, parentIndex...), i),
+			primaryKey: hasTagOption(field.Tag.Get("db"), "pk"),
+		})
+	}
+
+	for _, field := range embedded {
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+
+		if fieldType.Kind() != reflect.Struct {
+			continue
+		}
+
+		fields = appendColumnFields(fields, fieldType, append(append([]int{}, parentIndex...), field.Index...), seen)
+	}
+
+	return fields
+}
+
+// columnName returns the column of a field from its `db` tag, options after a comma are ignored
+func columnName(field reflect.StructField) (string, bool) {
+	tag, ok := field.Tag.Lookup("db")
+	if !ok {
+		return "", false
+	}
+
+	column, _, _ := strings.Cut(tag, ",")
+	if column == "" || column == "-" {
+		return "", false
+	}
+
+	return column, true
+}
+
+// hasTagOption reports whether a tag has the option after its name, e.g. pk in `db:"id,pk"`
+func hasTagOption(tag, option string) bool {
+	_, options, _ := strings.Cut(tag, ",")
+	for _, o := range strings.Split(options, ",") {
+		if o == option {
+			return true
+		}
+	}
+
+	return false
+}
+
+// structValue returns the struct behind v, dereferencing pointers
+func structValue(v any) (reflect.Value, bool) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return reflect.Value{}, false
+		}
+
+		rv = rv.Elem()
+	}
+
+	return rv, rv.Kind() == reflect.Struct
+}
+
+// fieldValue returns the value of a struct field, nil pointers are returned as nil
+// and other pointers are dereferenced
+func fieldValue(value reflect.Value) any {
+	if value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return nil
+		}
+
+		return value.Elem().Interface()
+	}
+
+	return value.Interface()
+}
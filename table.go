@@ -0,0 +1,119 @@
+package sqlcraft
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/techforge-lat/errortrace/v2"
+	"github.com/techforge-lat/errortrace/v2/errtype"
+)
+
+var ErrInvalidColumn = errors.New("invalid column")
+
+// Table declares a table once, the queries created from it share its columns, mapping,
+// validation and soft-delete, audit and tenant settings
+type Table struct {
+	Name string
+	// Columns are the table columns, they are selected by default
+	// and the only columns that can be inserted or updated
+	Columns []string
+	// PrimaryKey columns are never set by UpdateQuery.WithStruct and UpdateQuery.WithChanges
+	PrimaryKey []string
+
+	SQLColumnByDomainField map[string]string
+	FieldPolicies          FieldPolicies
+	ColumnTypes            ColumnTypes
+
+	SoftDeleteColumn string
+	Audit            *Audit
+	Tenant           *TenantScope
+}
+
+// Select returns a SelectQuery from the table with the given columns, or every table column if none is given
+func (t Table) Select(columns ...string) SelectQuery {
+	if len(columns) == 0 {
+		columns = t.Columns
+	}
+
+	s := Select(columns...).
+		From(t.Name).
+		SQLColumnByDomainField(t.SQLColumnByDomainField).
+		FieldPolicies(t.FieldPolicies).
+		ColumnTypes(t.ColumnTypes).
+		SoftDelete(t.SoftDeleteColumn)
+
+	if t.Tenant != nil {
+		s = s.WithTenantScope(*t.Tenant)
+	}
+
+	return s
+}
+
+func (t Table) InsertInto() InsertQuery {
+	i := InsertInto(t.Name)
+	i.knownColumns = t.Columns
+
+	if t.Audit != nil {
+		i = i.WithAudit(*t.Audit)
+	}
+
+	if t.Tenant != nil {
+		i = i.WithTenantScope(*t.Tenant)
+	}
+
+	return i
+}
+
+func (t Table) Update() UpdateQuery {
+	u := Update(t.Name).
+		SQLColumnByDomainField(t.SQLColumnByDomainField).
+		FieldPolicies(t.FieldPolicies).
+		ColumnTypes(t.ColumnTypes).
+		SoftDelete(t.SoftDeleteColumn)
+	u.knownColumns = t.Columns
+	u.primaryKey = t.PrimaryKey
+
+	if t.Audit != nil {
+		u = u.WithAudit(*t.Audit)
+	}
+
+	if t.Tenant != nil {
+		u = u.WithTenantScope(*t.Tenant)
+	}
+
+	return u
+}
+
+// DeleteFrom returns a DeleteQuery from the table, a soft delete when the table has a SoftDeleteColumn
+func (t Table) DeleteFrom() DeleteQuery {
+	d := DeleteFrom(t.Name).
+		SQLColumnByDomainField(t.SQLColumnByDomainField).
+		FieldPolicies(t.FieldPolicies).
+		ColumnTypes(t.ColumnTypes).
+		SoftDelete(t.SoftDeleteColumn)
+
+	if t.Tenant != nil {
+		d = d.WithTenantScope(*t.Tenant)
+	}
+
+	return d
+}
+
+// checkColumns returns an error when a column is not one of the known columns, any column is valid when there are no known columns
+func checkColumns(knownColumns, columns []string) error {
+	if len(knownColumns) == 0 {
+		return nil
+	}
+
+	for _, column := range columns {
+		if !slices.Contains(knownColumns, column) {
+			return errortrace.
+				OnError(ErrInvalidColumn).
+				WithCode(errtype.UnprocessableEntity).
+				WithMessage(fmt.Sprintf("column %q not found", column))
+		}
+	}
+
+	return nil
+}
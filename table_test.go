@@ -0,0 +1,98 @@
+package sqlcraft
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/techforge-lat/dafi/v2"
+)
+
+func TestTable(t *testing.T) {
+	users := Table{
+		Name:       "users",
+		Columns:    []string{"id", "email", "age", "deleted_at"},
+		PrimaryKey: []string{"id"},
+		SQLColumnByDomainField: map[string]string{
+			"id":    "id",
+			"email": "email",
+			"age":   "age",
+		},
+		ColumnTypes:      ColumnTypes{"age": IntColumnType},
+		SoftDeleteColumn: "deleted_at",
+	}
+
+	type user struct {
+		ID    int    `db:"id"`
+		Email string `db:"email"`
+	}
+
+	tests := []struct {
+		name    string
+		query   interface{ ToSQL() (Result, error) }
+		want    Result
+		wantErr bool
+	}{
+		{
+			name:  "select every column",
+			query: users.Select().Where(dafi.Filter{Field: "age", Operator: dafi.GreaterOrEqual, Value: "18"}),
+			want: Result{
+				Sql:  "SELECT id, email, age, deleted_at FROM users WHERE (age >= $1) AND users.deleted_at IS NULL",
+				Args: []any{int64(18)},
+			},
+		},
+		{
+			name:    "select by unknown domain field",
+			query:   users.Select("id").Where(dafi.Filter{Field: "password", Value: "secret"}),
+			wantErr: true,
+		},
+		{
+			name:  "insert",
+			query: users.InsertInto().WithColumns("email", "age").WithValues("hernan_rm@outlook.es", 30).Returning("id"),
+			want: Result{
+				Sql:  "INSERT INTO users (email, age) VALUES ($1, $2) RETURNING id",
+				Args: []any{"hernan_rm@outlook.es", 30},
+			},
+		},
+		{
+			name:    "insert unknown column",
+			query:   users.InsertInto().WithColumns("password").WithValues("secret"),
+			wantErr: true,
+		},
+		{
+			name:  "update from struct skips the primary key",
+			query: users.Update().WithStruct(user{ID: 1, Email: "brownie@gmail.com"}).Where(dafi.Filter{Field: "id", Value: "1"}),
+			want: Result{
+				Sql:  "UPDATE users SET email = $1 WHERE (id = $2) AND users.deleted_at IS NULL",
+				Args: []any{"brownie@gmail.com", "1"},
+			},
+		},
+		{
+			name:    "update unknown column",
+			query:   users.Update().Set("password", "secret").Where(dafi.Filter{Field: "id", Value: 1}),
+			wantErr: true,
+		},
+		{
+			name:  "soft delete",
+			query: users.DeleteFrom().Where(dafi.Filter{Field: "id", Value: 1}),
+			want: Result{
+				Sql:  "UPDATE users SET deleted_at = now() WHERE (id = $1) AND users.deleted_at IS NULL",
+				Args: []any{1},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.query.ToSQL()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ToSQL() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ToSQL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
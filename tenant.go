@@ -0,0 +1,63 @@
+package sqlcraft
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/techforge-lat/errortrace/v2"
+	"github.com/techforge-lat/errortrace/v2/errtype"
+)
+
+var ErrMissingTenant = errors.New("missing tenant")
+
+// TenantScope scopes every query of a multi-tenant table to a single tenant:
+// SELECT, UPDATE and DELETE filter by the tenant column and INSERT sets it,
+// the queries are refused when there is no tenant
+type TenantScope struct {
+	Column string
+	// FromContext returns the tenant from the query context
+	FromContext func(ctx context.Context) (any, bool)
+}
+
+// tenancy is the tenant configuration of a query
+type tenancy struct {
+	scope  *TenantScope
+	tenant any
+	isSet  bool
+}
+
+// value returns the tenant of the query, scoped is false when the table is not scoped
+func (t tenancy) value(ctx context.Context) (tenant any, scoped bool, err error) {
+	if t.scope == nil {
+		return nil, false, nil
+	}
+
+	tenant, ok := t.tenant, t.isSet
+	if !ok && t.scope.FromContext != nil {
+		if ctx == nil {
+			ctx = context.Background()
+		}
+
+		tenant, ok = t.scope.FromContext(ctx)
+	}
+
+	if !ok || isNull(tenant) {
+		return nil, true, errortrace.
+			OnError(ErrMissingTenant).
+			WithCode(errtype.Forbidden).
+			WithMessage(fmt.Sprintf("a tenant is required to query by %q", t.scope.Column))
+	}
+
+	return tenant, true, nil
+}
+
+// scopes returns the predicate that restricts the rows of the table to the tenant
+func (t tenancy) scopes(ctx context.Context, table string) ([]Expr, error) {
+	tenant, scoped, err := t.value(ctx)
+	if err != nil || !scoped {
+		return nil, err
+	}
+
+	return []Expr{Raw(qualifiedColumn(table, t.scope.Column)+" = $1", tenant)}, nil
+}
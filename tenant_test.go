@@ -0,0 +1,118 @@
+package sqlcraft
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/techforge-lat/dafi/v2"
+	"github.com/techforge-lat/errortrace/v2"
+)
+
+type tenantKey struct{}
+
+func TestTenantScope(t *testing.T) {
+	scope := TenantScope{
+		Column: "tenant_id",
+		FromContext: func(ctx context.Context) (any, bool) {
+			tenant, ok := ctx.Value(tenantKey{}).(int)
+
+			return tenant, ok
+		},
+	}
+	ctx := context.WithValue(context.Background(), tenantKey{}, 10)
+
+	tests := []struct {
+		name    string
+		query   interface{ ToSQL() (Result, error) }
+		want    Result
+		wantErr error
+	}{
+		{
+			name:  "select",
+			query: Select("id", "email").From("users").WithTenantScope(scope).WithContext(ctx).Where(dafi.Filter{Field: "email", Value: "hernan_rm@outlook.es"}),
+			want: Result{
+				Sql:  "SELECT id, email FROM users WHERE (email = $1) AND users.tenant_id = $2",
+				Args: []any{"hernan_rm@outlook.es", 10},
+			},
+		},
+		{
+			name:  "select with soft delete",
+			query: Select("id").From("users").WithTenantScope(scope).ForTenant(20).SoftDelete("deleted_at"),
+			want: Result{
+				Sql:  "SELECT id FROM users WHERE users.tenant_id = $1 AND users.deleted_at IS NULL",
+				Args: []any{20},
+			},
+		},
+		{
+			name:  "select with join qualifies the tenant column",
+			query: Select("u.id", "o.id").From("users u").InnerJoin("orders o", "o.user_id = u.id").WithTenantScope(scope).ForTenant(20),
+			want: Result{
+				Sql:  "SELECT u.id, o.id FROM users u INNER JOIN orders o ON o.user_id = u.id WHERE u.tenant_id = $1",
+				Args: []any{20},
+			},
+		},
+		{
+			name:    "select without tenant",
+			query:   Select("id").From("users").WithTenantScope(scope),
+			wantErr: ErrMissingTenant,
+		},
+		{
+			name:  "update",
+			query: Update("users").Set("email", "brownie@gmail.com").Where(dafi.Filter{Field: "id", Value: 1}).WithTenantScope(scope).WithContext(ctx),
+			want: Result{
+				Sql:  "UPDATE users SET email = $1 WHERE (id = $2) AND users.tenant_id = $3",
+				Args: []any{"brownie@gmail.com", 1, 10},
+			},
+		},
+		{
+			name:  "delete",
+			query: DeleteFrom("users").Where(dafi.Filter{Field: "id", Value: 1}).WithTenantScope(scope).WithContext(ctx),
+			want: Result{
+				Sql:  "DELETE FROM users WHERE (id = $1) AND users.tenant_id = $2",
+				Args: []any{1, 10},
+			},
+		},
+		{
+			name:    "delete without tenant",
+			query:   DeleteFrom("users").Where(dafi.Filter{Field: "id", Value: 1}).WithTenantScope(scope).ForTenant(nil),
+			wantErr: ErrMissingTenant,
+		},
+		{
+			name:  "insert overwrites the tenant column",
+			query: InsertInto("users").WithColumns("email", "tenant_id").WithValues("hernan_rm@outlook.es", 99).WithValues("brownie@gmail.com", 98).WithTenantScope(scope).WithContext(ctx),
+			want: Result{
+				Sql:  "INSERT INTO users (email, tenant_id) VALUES ($1, $2), ($3, $4)",
+				Args: []any{"hernan_rm@outlook.es", 10, "brownie@gmail.com", 10},
+			},
+		},
+		{
+			name:  "insert adds the tenant column",
+			query: InsertInto("users").WithColumns("email").WithValues("hernan_rm@outlook.es").WithTenantScope(scope).WithContext(ctx),
+			want: Result{
+				Sql:  "INSERT INTO users (email, tenant_id) VALUES ($1, $2)",
+				Args: []any{"hernan_rm@outlook.es", 10},
+			},
+		},
+		{
+			name:    "insert without tenant",
+			query:   InsertInto("users").WithColumns("email").WithValues("hernan_rm@outlook.es").WithTenantScope(scope),
+			wantErr: ErrMissingTenant,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.query.ToSQL()
+			if !errortrace.Is(err, tt.wantErr) {
+				t.Errorf("ToSQL() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ToSQL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
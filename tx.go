@@ -0,0 +1,152 @@
+package sqlcraft
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"time"
+
+	"github.com/techforge-lat/errortrace/v2"
+	"github.com/techforge-lat/errortrace/v2/errtype"
+)
+
+var ErrTxNotSupported = errors.New("db cannot begin transactions")
+
+// RetryableSQLStates are the SQLSTATE codes retried by default: serialization_failure and deadlock_detected
+var RetryableSQLStates = []string{"40001", "40P01"}
+
+// TxBeginner begins transactions, it is implemented by *sql.DB and *sql.Conn
+type TxBeginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// TxOptions configures a transaction started by WithTx
+type TxOptions struct {
+	Isolation sql.IsolationLevel
+	ReadOnly  bool
+
+	// MaxRetries is how many times the transaction is run again when it fails with a retryable SQLSTATE,
+	// zero means it is not retried
+	MaxRetries int
+	// RetryableStates are the SQLSTATE codes that make the transaction run again, RetryableSQLStates if empty
+	RetryableStates []string
+	// Backoff returns how long to wait before the given retry, starting at 1,
+	// by default it doubles from 10ms up to 1s with jitter
+	Backoff func(retry int) time.Duration
+}
+
+// Tx is the transaction given to the WithTx callback, a WithTx call on it runs inside a savepoint
+type Tx struct {
+	*sql.Tx
+
+	depth int
+}
+
+// WithTx runs fn inside a transaction, committing it when fn returns nil and rolling it back
+// when fn returns an error or panics, the panic is propagated after the rollback
+//
+// When db is a *Tx the callback runs inside a savepoint of that transaction instead,
+// so a failing inner call only undoes its own changes, the options are ignored and it is never retried,
+// since a retryable failure aborts the whole transaction which is retried by the outermost call
+func WithTx(ctx context.Context, db DB, opts TxOptions, fn func(tx *Tx) error) error {
+	if tx, ok := db.(*Tx); ok {
+		return withSavepoint(ctx, tx, fn)
+	}
+
+	beginner, ok := db.(TxBeginner)
+	if !ok {
+		return errortrace.
+			OnError(ErrTxNotSupported).
+			WithCode(errtype.InternalError).
+			WithMessage(fmt.Sprintf("%T cannot begin transactions", db))
+	}
+
+	for retry := 0; ; retry++ {
+		err := runTx(ctx, beginner, opts, fn)
+		if err == nil || retry >= opts.MaxRetries || !opts.isRetryable(err) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(opts.backoff(retry + 1)):
+		}
+	}
+}
+
+func runTx(ctx context.Context, beginner TxBeginner, opts TxOptions, fn func(tx *Tx) error) (err error) {
+	sqlTx, err := beginner.BeginTx(ctx, &sql.TxOptions{Isolation: opts.Isolation, ReadOnly: opts.ReadOnly})
+	if err != nil {
+		return WrapDBError(err)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			_ = sqlTx.Rollback()
+			panic(r)
+		}
+	}()
+
+	if err := fn(&Tx{Tx: sqlTx}); err != nil {
+		_ = sqlTx.Rollback()
+
+		return err
+	}
+
+	return WrapDBError(sqlTx.Commit())
+}
+
+func withSavepoint(ctx context.Context, tx *Tx, fn func(tx *Tx) error) (err error) {
+	inner := &Tx{Tx: tx.Tx, depth: tx.depth + 1}
+	savepoint := fmt.Sprintf("sqlcraft_savepoint_%d", inner.depth)
+
+	if _, err := tx.ExecContext(ctx, "SAVEPOINT "+savepoint); err != nil {
+		return WrapDBError(err)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			_, _ = tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+savepoint)
+			panic(r)
+		}
+	}()
+
+	if err := fn(inner); err != nil {
+		_, _ = tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+savepoint)
+
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "RELEASE SAVEPOINT "+savepoint)
+
+	return WrapDBError(err)
+}
+
+func (o TxOptions) isRetryable(err error) bool {
+	states := o.RetryableStates
+	if len(states) == 0 {
+		states = RetryableSQLStates
+	}
+
+	state := SQLState(err)
+
+	return state != "" && slices.Contains(states, state)
+}
+
+func (o TxOptions) backoff(retry int) time.Duration {
+	if o.Backoff != nil {
+		return o.Backoff(retry)
+	}
+
+	wait := 10 * time.Millisecond
+	for i := 1; i < retry && wait < time.Second; i++ {
+		wait *= 2
+	}
+	wait = min(wait, time.Second)
+
+	return wait/2 + rand.N(wait/2+1)
+}
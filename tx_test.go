@@ -0,0 +1,175 @@
+package sqlcraft
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/techforge-lat/errortrace/v2"
+)
+
+func executedSQL(database *fakeDatabase) []string {
+	var statements []string
+	for _, statement := range database.executed() {
+		statements = append(statements, statement.Sql)
+	}
+
+	return statements
+}
+
+func TestWithTx(t *testing.T) {
+	errBusiness := errors.New("business rule failed")
+	noBackoff := func(int) time.Duration { return 0 }
+
+	tests := []struct {
+		name    string
+		errs    []error
+		opts    TxOptions
+		fn      func(tx *Tx) error
+		want    []string
+		wantErr error
+	}{
+		{
+			name: "commit",
+			fn: func(tx *Tx) error {
+				_, err := Exec(context.Background(), tx, Result{Sql: "UPDATE users SET active = true"})
+				return err
+			},
+			want: []string{"BEGIN", "UPDATE users SET active = true", "COMMIT"},
+		},
+		{
+			name:    "rollback on error",
+			fn:      func(tx *Tx) error { return errBusiness },
+			want:    []string{"BEGIN", "ROLLBACK"},
+			wantErr: errBusiness,
+		},
+		{
+			name: "nested calls use savepoints",
+			fn: func(tx *Tx) error {
+				_ = WithTx(context.Background(), tx, TxOptions{}, func(inner *Tx) error {
+					return WithTx(context.Background(), inner, TxOptions{}, func(*Tx) error { return errBusiness })
+				})
+
+				return WithTx(context.Background(), tx, TxOptions{}, func(*Tx) error { return nil })
+			},
+			want: []string{
+				"BEGIN",
+				"SAVEPOINT sqlcraft_savepoint_1",
+				"SAVEPOINT sqlcraft_savepoint_2",
+				"ROLLBACK TO SAVEPOINT sqlcraft_savepoint_2",
+				"ROLLBACK TO SAVEPOINT sqlcraft_savepoint_1",
+				"SAVEPOINT sqlcraft_savepoint_1",
+				"RELEASE SAVEPOINT sqlcraft_savepoint_1",
+				"COMMIT",
+			},
+		},
+		{
+			name: "retry on serialization failure",
+			errs: []error{nil, fakePgError{code: "40001"}},
+			opts: TxOptions{MaxRetries: 2, Backoff: noBackoff},
+			fn: func(tx *Tx) error {
+				_, err := Exec(context.Background(), tx, Result{Sql: "UPDATE accounts SET balance = 0"})
+				return err
+			},
+			want: []string{
+				"BEGIN", "UPDATE accounts SET balance = 0", "ROLLBACK",
+				"BEGIN", "UPDATE accounts SET balance = 0", "COMMIT",
+			},
+		},
+		{
+			name: "retry on commit deadlock",
+			errs: []error{nil, fakePgError{code: "40P01"}},
+			opts: TxOptions{MaxRetries: 1, Backoff: noBackoff},
+			fn:   func(tx *Tx) error { return nil },
+			want: []string{"BEGIN", "COMMIT", "BEGIN", "COMMIT"},
+		},
+		{
+			name: "retries exhausted",
+			errs: []error{nil, fakePgError{code: "40001"}, nil, nil, fakePgError{code: "40001"}},
+			opts: TxOptions{MaxRetries: 1, Backoff: noBackoff},
+			fn: func(tx *Tx) error {
+				_, err := Exec(context.Background(), tx, Result{Sql: "UPDATE accounts SET balance = 0"})
+				return err
+			},
+			want: []string{
+				"BEGIN", "UPDATE accounts SET balance = 0", "ROLLBACK",
+				"BEGIN", "UPDATE accounts SET balance = 0", "ROLLBACK",
+			},
+			wantErr: fakePgError{code: "40001"},
+		},
+		{
+			name: "non retryable error",
+			errs: []error{nil, fakePgError{code: "23505"}},
+			opts: TxOptions{MaxRetries: 3, Backoff: noBackoff},
+			fn: func(tx *Tx) error {
+				_, err := Exec(context.Background(), tx, Result{Sql: "INSERT INTO users (id) VALUES (1)"})
+				return err
+			},
+			want:    []string{"BEGIN", "INSERT INTO users (id) VALUES (1)", "ROLLBACK"},
+			wantErr: fakePgError{code: "23505"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			database := &fakeDatabase{errs: tt.errs}
+			db := newFakeDB(t, database)
+
+			err := WithTx(context.Background(), db, tt.opts, tt.fn)
+			if !errortrace.Is(err, tt.wantErr) {
+				t.Fatalf("WithTx() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got := executedSQL(database); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("WithTx() executed = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithTx_Panic(t *testing.T) {
+	database := &fakeDatabase{}
+	db := newFakeDB(t, database)
+
+	defer func() {
+		if r := recover(); r != "boom" {
+			t.Errorf("WithTx() recovered = %v, want boom", r)
+		}
+
+		if got, want := executedSQL(database), []string{"BEGIN", "ROLLBACK"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("WithTx() executed = %q, want %q", got, want)
+		}
+	}()
+
+	_ = WithTx(context.Background(), db, TxOptions{}, func(*Tx) error { panic("boom") })
+}
+
+func TestWithTx_NotSupported(t *testing.T) {
+	db := newFakeDB(t, &fakeDatabase{})
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	sqlTx, err := conn.BeginTx(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sqlTx.Rollback()
+
+	err = WithTx(context.Background(), sqlTx, TxOptions{}, func(*Tx) error { return nil })
+	if !errortrace.Is(err, ErrTxNotSupported) {
+		t.Errorf("WithTx() error = %v, want %v", err, ErrTxNotSupported)
+	}
+}
+
+func TestTxOptions_backoff(t *testing.T) {
+	opts := TxOptions{}
+	for retry, upper := range map[int]time.Duration{1: 10 * time.Millisecond, 3: 40 * time.Millisecond, 100: time.Second} {
+		if got := opts.backoff(retry); got < upper/2 || got > upper {
+			t.Errorf("backoff(%d) = %v, want between %v and %v", retry, got, upper/2, upper)
+		}
+	}
+}
@@ -1,12 +1,22 @@
 package sqlcraft
 
 import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
 	"strconv"
 	"strings"
 
 	"github.com/techforge-lat/dafi/v2"
 )
 
+var (
+	ErrNothingToUpdate = errors.New("nothing to update")
+	ErrInvalidStruct   = errors.New("value is not a struct")
+	ErrStructMismatch  = errors.New("structs to compare are not of the same type")
+)
+
 type UpdateQuery struct {
 	table           string
 	columns         []string
@@ -14,9 +24,31 @@ type UpdateQuery struct {
 	values          []any
 
 	isPartialUpdate bool
+	from            []Expr
+	dialect         Dialect
+
+	versionColumn   string
+	expectedVersion any
+
+	softDeleteColumn string
+	deletedRows      deletedRows
+
+	knownColumns []string
+	primaryKey   []string
+
+	ctx     context.Context
+	audit   *Audit
+	tenancy tenancy
 
 	sqlColumnByDomainField map[string]string
+	fieldPolicies          FieldPolicies
+	columnTypes            ColumnTypes
+	emptyIn                EmptyInBehavior
+	emptyNotIn             EmptyInBehavior
 	filters                dafi.Filters
+	allowFullTable         bool
+
+	err error
 }
 
 func Update(table string) UpdateQuery {
@@ -34,12 +66,98 @@ func (u UpdateQuery) WithColumns(columns ...string) UpdateQuery {
 	return u
 }
 
+// WithValues sets the values of the columns, a value can be an Expr or a ColumnRef
+// to set the column with an expression or another column instead of a bound argument
 func (u UpdateQuery) WithValues(values ...any) UpdateQuery {
 	u.values = values
 
 	return u
 }
 
+// Set adds a column with its value, which can be an Expr,
+// e.g. Set("views", Raw("views + $1", 1)) or Set("updated_at", Raw("now()"))
+func (u UpdateQuery) Set(column string, value any) UpdateQuery {
+	values := slices.Clone(u.values)
+	// columns given without values keep their placeholder
+	for len(values) < len(u.columns) {
+		values = append(values, placeholder{})
+	}
+
+	u.columns = append(slices.Clone(u.columns), column)
+	u.values = append(values, value)
+
+	return u
+}
+
+// WithStruct sets the columns and values from the `db` tagged fields of a struct,
+// only non-zero fields and non-nil pointers are set, pointers are dereferenced.
+// The primary key, tagged like `db:"id,pk"` or set by Table, is never set
+func (u UpdateQuery) WithStruct(v any) UpdateQuery {
+	rv, ok := structValue(v)
+	if !ok {
+		u.err = ErrInvalidStruct
+
+		return u
+	}
+
+	u.columns, u.values = []string{}, []any{}
+	for _, field := range columnFields(rv.Type()) {
+		if field.primaryKey || slices.Contains(u.primaryKey, field.column) {
+			continue
+		}
+
+		value, err := rv.FieldByIndexErr(field.index)
+		if err != nil || value.IsZero() {
+			continue
+		}
+
+		u.columns = append(u.columns, field.column)
+		u.values = append(u.values, fieldValue(value))
+	}
+
+	return u
+}
+
+// WithChanges sets the columns and values from the `db` tagged fields that changed between
+// two structs of the same type, the values are taken from after, the primary key is never set
+func (u UpdateQuery) WithChanges(before, after any) UpdateQuery {
+	beforeValue, beforeOk := structValue(before)
+	afterValue, afterOk := structValue(after)
+	if !beforeOk || !afterOk {
+		u.err = ErrInvalidStruct
+
+		return u
+	}
+
+	if beforeValue.Type() != afterValue.Type() {
+		u.err = ErrStructMismatch
+
+		return u
+	}
+
+	u.columns, u.values = []string{}, []any{}
+	for _, field := range columnFields(afterValue.Type()) {
+		if field.primaryKey || slices.Contains(u.primaryKey, field.column) {
+			continue
+		}
+
+		beforeField, beforeErr := beforeValue.FieldByIndexErr(field.index)
+		afterField, afterErr := afterValue.FieldByIndexErr(field.index)
+		if afterErr != nil {
+			continue
+		}
+
+		if beforeErr == nil && reflect.DeepEqual(beforeField.Interface(), afterField.Interface()) {
+			continue
+		}
+
+		u.columns = append(u.columns, field.column)
+		u.values = append(u.values, fieldValue(afterField))
+	}
+
+	return u
+}
+
 func (u UpdateQuery) Where(filters ...dafi.Filter) UpdateQuery {
 	u.filters = filters
 
@@ -52,6 +170,124 @@ func (u UpdateQuery) SQLColumnByDomainField(sqlColumnByDomainField map[string]st
 	return u
 }
 
+// FieldPolicies restricts the domain fields a client can filter by
+func (u UpdateQuery) FieldPolicies(fieldPolicies FieldPolicies) UpdateQuery {
+	u.fieldPolicies = fieldPolicies
+
+	return u
+}
+
+// ColumnTypes coerces the string filter values into the type of their sql column
+func (u UpdateQuery) ColumnTypes(columnTypes ColumnTypes) UpdateQuery {
+	u.columnTypes = columnTypes
+
+	return u
+}
+
+// OnEmptyIn defines how an IN filter with an empty list is built
+func (u UpdateQuery) OnEmptyIn(behavior EmptyInBehavior) UpdateQuery {
+	u.emptyIn = behavior
+
+	return u
+}
+
+// OnEmptyNotIn defines how a NOT IN filter with an empty list is built
+func (u UpdateQuery) OnEmptyNotIn(behavior EmptyInBehavior) UpdateQuery {
+	u.emptyNotIn = behavior
+
+	return u
+}
+
+// From adds tables to update from, their join conditions go in the WHERE filters,
+// e.g. Update("t").From("other").Where(dafi.Filter{Field: "t.id", Value: ColumnRef("other.t_id")})
+func (u UpdateQuery) From(tables ...string) UpdateQuery {
+	u.from = append(slices.Clone(u.from), tablesToExprs(tables)...)
+
+	return u
+}
+
+// FromSubquery adds a subquery to update from with the given alias
+func (u UpdateQuery) FromSubquery(subquery Result, alias string) UpdateQuery {
+	u.from = append(slices.Clone(u.from), aliasedSubquery(subquery, alias))
+
+	return u
+}
+
+// WithVersion enables optimistic locking: the row is only updated when the version column
+// still has the expected value, and the version is incremented by one.
+// Use CheckVersion with the execution result, or sqlcraftpgx.CheckVersion with the command tag, to detect a conflict
+func (u UpdateQuery) WithVersion(column string, expected any) UpdateQuery {
+	u.versionColumn = column
+	u.expectedVersion = expected
+
+	return u
+}
+
+// SoftDelete marks the table as soft-deleted by the given column,
+// rows with a non-null column are not updated unless WithDeleted or OnlyDeleted are used
+func (u UpdateQuery) SoftDelete(column string) UpdateQuery {
+	u.softDeleteColumn = column
+
+	return u
+}
+
+// WithDeleted updates soft-deleted rows too
+func (u UpdateQuery) WithDeleted() UpdateQuery {
+	u.deletedRows = includeDeletedRows
+
+	return u
+}
+
+// OnlyDeleted updates only soft-deleted rows
+func (u UpdateQuery) OnlyDeleted() UpdateQuery {
+	u.deletedRows = onlyDeletedRows
+
+	return u
+}
+
+// WithContext sets the context used to resolve the audit actor and the tenant
+func (u UpdateQuery) WithContext(ctx context.Context) UpdateQuery {
+	u.ctx = ctx
+
+	return u
+}
+
+// WithAudit fills the updated audit columns, columns already provided are left untouched
+func (u UpdateQuery) WithAudit(audit Audit) UpdateQuery {
+	u.audit = &audit
+
+	return u
+}
+
+// WithTenantScope scopes the query to the tenant from ForTenant or the query context
+func (u UpdateQuery) WithTenantScope(scope TenantScope) UpdateQuery {
+	u.tenancy.scope = &scope
+
+	return u
+}
+
+// ForTenant sets the tenant of a query built WithTenantScope, instead of taking it from the context
+func (u UpdateQuery) ForTenant(tenant any) UpdateQuery {
+	u.tenancy.tenant = tenant
+	u.tenancy.isSet = true
+
+	return u
+}
+
+// WithDialect sets the sql dialect, PostgreSQL by default
+func (u UpdateQuery) WithDialect(dialect Dialect) UpdateQuery {
+	u.dialect = dialect
+
+	return u
+}
+
+// AllowFullTable allows to build the query without filters, affecting every row of the table
+func (u UpdateQuery) AllowFullTable() UpdateQuery {
+	u.allowFullTable = true
+
+	return u
+}
+
 func (u UpdateQuery) Returning(columns ...string) UpdateQuery {
 	u.returningValues = columns
 
@@ -65,54 +301,235 @@ func (u UpdateQuery) WithPartialUpdate() UpdateQuery {
 }
 
 func (u UpdateQuery) ToSQL() (Result, error) {
+	if u.err != nil {
+		return Result{}, u.err
+	}
+
 	if len(u.values) > 0 && len(u.values) != len(u.columns) {
 		return Result{}, ErrMissMatchValues
 	}
 
+	if u.dialect == MySQL && len(u.returningValues) > 0 {
+		return Result{}, ErrReturningNotSupported
+	}
+
+	if err := checkColumns(u.knownColumns, u.columns); err != nil {
+		return Result{}, err
+	}
+
+	if !u.hasChanges() {
+		return Result{}, ErrNothingToUpdate
+	}
+
+	if u.versionColumn != "" {
+		u = u.Set(u.versionColumn, Raw(qualifiedColumn(u.table, u.versionColumn)+" + 1"))
+	}
+
+	if u.audit != nil {
+		for _, audit := range u.audit.updateColumns(u.ctx) {
+			if audit.column != "" && !slices.Contains(u.columns, audit.column) {
+				// set as an Optional so a partial update does not keep the previous value
+				u = u.Set(audit.column, Some(audit.value))
+			}
+		}
+	}
+
+	setResult, argCount, err := u.buildSet()
+	if err != nil {
+		return Result{}, err
+	}
+	args := setResult.Args
+
+	fromSQL, fromArgs := joinExprs(u.from, argCount)
+	argCount += len(fromArgs)
+	args = append(args, fromArgs...)
+
 	builder := strings.Builder{}
 
 	builder.WriteString("UPDATE ")
 	builder.WriteString(u.table)
+
+	if u.dialect == MySQL {
+		for _, from := range fromSQL {
+			builder.WriteString(" JOIN ")
+			builder.WriteString(from)
+		}
+	}
+
 	builder.WriteString(" SET ")
+	builder.WriteString(setResult.Sql)
+
+	if u.dialect != MySQL && len(fromSQL) > 0 {
+		builder.WriteString(" FROM ")
+		builder.WriteString(strings.Join(fromSQL, ", "))
+	}
+
+	whereResult, predicates, err := whereWithOptions(argCount, u.whereOptions(), u.filters...)
+	if err != nil {
+		return Result{}, err
+	}
+
+	if predicates == 0 && !u.allowFullTable {
+		return Result{}, ErrMissingFilters
+	}
+
+	scopes, err := u.scopes()
+	if err != nil {
+		return Result{}, err
+	}
+	whereResult = andScopes(whereResult, argCount, scopes...)
 
+	args = append(args, whereResult.Args...)
+	builder.WriteString(whereResult.Sql)
+
+	if len(u.returningValues) > 0 {
+		builder.WriteString(" RETURNING ")
+		builder.WriteString(strings.Join(u.returningValues, ", "))
+	}
+
+	return rebind(u.dialect, Result{
+		Sql:  builder.String(),
+		Args: args,
+	}), nil
+}
+
+// buildSet returns the SET assignments and the number of placeholders they use,
+// which can be greater than the args when the values were not provided
+func (u UpdateQuery) buildSet() (Result, int, error) {
+	builder := strings.Builder{}
+
+	args := []any{}
+	argCount := 0
+	setCount := 0
 	for i, column := range u.columns {
-		if u.isPartialUpdate {
+		var value any
+		hasValue := i < len(u.values)
+		if hasValue {
+			value = u.values[i]
+			_, isPlaceholder := value.(placeholder)
+			hasValue = !isPlaceholder
+		}
+
+		// an explicit Optional value always overrides the column
+		state, isPartialUpdate := optionalSet, u.isPartialUpdate
+		if opt, ok := value.(optional); ok {
+			state, value = opt.optional()
+			isPartialUpdate = false
+		}
+
+		if state == optionalUnset {
+			continue
+		}
+
+		if setCount > 0 {
+			builder.WriteString(", ")
+		}
+		setCount++
+
+		if state == optionalNull {
+			builder.WriteString(column)
+			builder.WriteString(" = NULL")
+
+			continue
+		}
+
+		if ref, ok := value.(ColumnRef); ok {
+			if err := ref.validate(); err != nil {
+				return Result{}, 0, err
+			}
+
+			builder.WriteString(column)
+			builder.WriteString(" = ")
+			builder.WriteString(string(ref))
+
+			continue
+		}
+
+		if expr, ok := value.(Expr); ok {
+			builder.WriteString(column)
+			builder.WriteString(" = ")
+			builder.WriteString(expr.rebase(argCount))
+
+			argCount += len(expr.Args)
+			args = append(args, expr.Args...)
+
+			continue
+		}
+
+		argCount++
+		if hasValue {
+			args = append(args, value)
+		}
+
+		if isPartialUpdate {
 			builder.WriteString(column)
 			builder.WriteString(" = ")
 			builder.WriteString("COALESCE(")
 			builder.WriteString("$")
-			builder.WriteString(strconv.Itoa(i + 1))
+			builder.WriteString(strconv.Itoa(argCount))
 			builder.WriteString(", ")
 			builder.WriteString(column)
 			builder.WriteString(")")
 		} else {
 			builder.WriteString(column)
 			builder.WriteString(" = $")
-			builder.WriteString(strconv.Itoa(i + 1))
+			builder.WriteString(strconv.Itoa(argCount))
 		}
+	}
 
-		if i+1 < len(u.columns) {
-			builder.WriteString(", ")
-		}
+	if setCount == 0 {
+		return Result{}, 0, ErrNothingToUpdate
+	}
+
+	return Result{
+		Sql:  builder.String(),
+		Args: args,
+	}, argCount, nil
+}
+
+// placeholder is the value of a column given without values, which is bound by the caller
+type placeholder struct{}
+
+// hasChanges reports whether there is any column to set besides the unset Optional values
+func (u UpdateQuery) hasChanges() bool {
+	if len(u.values) == 0 {
+		return len(u.columns) > 0
 	}
 
-	if len(u.filters) > 0 {
-		whereResult, err := WhereSafe(len(u.values), u.sqlColumnByDomainField, u.filters...)
-		if err != nil {
-			return Result{}, err
+	for _, value := range u.values {
+		if opt, ok := value.(optional); ok {
+			if state, _ := opt.optional(); state == optionalUnset {
+				continue
+			}
 		}
-		u.values = append(u.values, whereResult.Args...)
 
-		builder.WriteString(whereResult.Sql)
+		return true
 	}
 
-	if len(u.returningValues) > 0 {
-		builder.WriteString(" RETURNING ")
-		builder.WriteString(strings.Join(u.returningValues, ", "))
+	return false
+}
+
+// scopes returns the predicates added to the filters
+func (u UpdateQuery) scopes() ([]Expr, error) {
+	scopes, err := u.tenancy.scopes(u.ctx, u.table)
+	if err != nil {
+		return nil, err
 	}
 
-	return Result{
-		Sql:  builder.String(),
-		Args: u.values,
-	}, nil
+	scopes = append(scopes, softDeleteScopes(qualifiedColumn(u.table, u.softDeleteColumn), u.deletedRows)...)
+	if u.versionColumn != "" {
+		scopes = append(scopes, Raw(qualifiedColumn(u.table, u.versionColumn)+" = $1", u.expectedVersion))
+	}
+
+	return scopes, nil
+}
+
+func (u UpdateQuery) whereOptions() WhereOptions {
+	return WhereOptions{
+		SQLColumnByDomainField: u.sqlColumnByDomainField,
+		FieldPolicies:          u.fieldPolicies,
+		ColumnTypes:            u.columnTypes,
+		EmptyIn:                u.emptyIn,
+		EmptyNotIn:             u.emptyNotIn,
+	}
 }
@@ -7,7 +7,27 @@ import (
 	"github.com/techforge-lat/dafi/v2"
 )
 
+type employeeAudit struct {
+	UpdatedBy string `db:"updated_by"`
+}
+
+type employee struct {
+	employeeAudit
+	ID       uint    `db:"-"`
+	Name     string  `db:"name"`
+	Nickname *string `db:"nickname"`
+	Salary   float64 `db:"salary"`
+	Internal string
+}
+
+type taggedEmployee struct {
+	ID   uint   `db:"id,pk"`
+	Name string `db:"name"`
+}
+
 func TestUpdateQuery_ToSQL(t *testing.T) {
+	nickname := "brownie"
+
 	tests := []struct {
 		name    string
 		query   UpdateQuery
@@ -16,7 +36,7 @@ func TestUpdateQuery_ToSQL(t *testing.T) {
 	}{
 		{
 			name:  "update one field",
-			query: Update("employees").WithColumns("salary").WithValues(4000),
+			query: Update("employees").WithColumns("salary").WithValues(4000).AllowFullTable(),
 			want: Result{
 				Sql:  "UPDATE employees SET salary = $1",
 				Args: []any{4000},
@@ -25,7 +45,7 @@ func TestUpdateQuery_ToSQL(t *testing.T) {
 		},
 		{
 			name:  "update two fields",
-			query: Update("employees").WithColumns("salary", "name").WithValues(4000, "Hernan"),
+			query: Update("employees").WithColumns("salary", "name").WithValues(4000, "Hernan").AllowFullTable(),
 			want: Result{
 				Sql:  "UPDATE employees SET salary = $1, name = $2",
 				Args: []any{4000, "Hernan"},
@@ -34,7 +54,7 @@ func TestUpdateQuery_ToSQL(t *testing.T) {
 		},
 		{
 			name:  "update two fields with returning",
-			query: Update("employees").WithColumns("salary", "name").WithValues(4000, "Hernan").Returning("id"),
+			query: Update("employees").WithColumns("salary", "name").WithValues(4000, "Hernan").Returning("id").AllowFullTable(),
 			want: Result{
 				Sql:  "UPDATE employees SET salary = $1, name = $2 RETURNING id",
 				Args: []any{4000, "Hernan"},
@@ -43,7 +63,7 @@ func TestUpdateQuery_ToSQL(t *testing.T) {
 		},
 		{
 			name:  "update two fields with partial update",
-			query: Update("employees").WithColumns("salary", "name").WithValues(4000, "Hernan").WithPartialUpdate(),
+			query: Update("employees").WithColumns("salary", "name").WithValues(4000, "Hernan").WithPartialUpdate().AllowFullTable(),
 			want: Result{
 				Sql:  "UPDATE employees SET salary = COALESCE($1, salary), name = COALESCE($2, name)",
 				Args: []any{4000, "Hernan"},
@@ -52,7 +72,7 @@ func TestUpdateQuery_ToSQL(t *testing.T) {
 		},
 		{
 			name:  "update without providen values",
-			query: Update("employees").WithColumns("salary", "name").WithPartialUpdate(),
+			query: Update("employees").WithColumns("salary", "name").WithPartialUpdate().AllowFullTable(),
 			want: Result{
 				Sql:  "UPDATE employees SET salary = COALESCE($1, salary), name = COALESCE($2, name)",
 				Args: []any{},
@@ -86,6 +106,177 @@ func TestUpdateQuery_ToSQL(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name:  "update with optional values",
+			query: Update("employees").WithColumns("salary", "nickname", "name").WithValues(Some(4000), Null[string](), Optional[string]{}).Where(dafi.Filter{Field: "id", Value: 1}),
+			want: Result{
+				Sql:  "UPDATE employees SET salary = $1, nickname = NULL WHERE id = $2",
+				Args: []any{4000, 1},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "optional values are not coalesced on partial update",
+			query: Update("employees").WithColumns("name", "salary", "nickname").WithValues(Optional[string]{}, 4000, Some("brownie")).WithPartialUpdate().AllowFullTable(),
+			want: Result{
+				Sql:  "UPDATE employees SET salary = COALESCE($1, salary), nickname = $2",
+				Args: []any{4000, "brownie"},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "update from struct non-zero fields",
+			query: Update("employees").WithStruct(&employee{ID: 1, Nickname: &nickname, Salary: 4000, employeeAudit: employeeAudit{UpdatedBy: "admin"}}).Where(dafi.Filter{Field: "id", Value: 1}),
+			want: Result{
+				Sql:  "UPDATE employees SET nickname = $1, salary = $2, updated_by = $3 WHERE id = $4",
+				Args: []any{"brownie", float64(4000), "admin", 1},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "update from struct changes",
+			query: Update("employees").WithChanges(employee{Name: "Hernan", Nickname: &nickname, Salary: 4000}, employee{Name: "Hernan", Salary: 5000}).AllowFullTable(),
+			want: Result{
+				Sql:  "UPDATE employees SET nickname = $1, salary = $2",
+				Args: []any{nil, float64(5000)},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "update from struct skips the pk tagged column",
+			query: Update("employees").WithStruct(taggedEmployee{ID: 5, Name: "Hernan"}).Where(dafi.Filter{Field: "id", Value: 5}),
+			want: Result{
+				Sql:  "UPDATE employees SET name = $1 WHERE id = $2",
+				Args: []any{"Hernan", 5},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "update from struct changes skips the pk tagged column",
+			query: Update("employees").WithChanges(taggedEmployee{ID: 5, Name: "Hernan"}, taggedEmployee{ID: 6, Name: "Brownie"}).Where(dafi.Filter{Field: "id", Value: 5}),
+			want: Result{
+				Sql:  "UPDATE employees SET name = $1 WHERE id = $2",
+				Args: []any{"Brownie", 5},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "error nothing changed",
+			query:   Update("employees").WithChanges(employee{Name: "Hernan"}, employee{Name: "Hernan"}),
+			want:    Result{},
+			wantErr: true,
+		},
+		{
+			name:    "error struct mismatch",
+			query:   Update("employees").WithChanges(employee{}, employeeAudit{}),
+			want:    Result{},
+			wantErr: true,
+		},
+		{
+			name: "update with expressions",
+			query: Update("posts").
+				Set("title", "Hello").
+				Set("views", Raw("views + $1", 1)).
+				Set("updated_at", Raw("now()")).
+				Set("category_id", Default).
+				Set("author_id", Subquery(Result{Sql: "SELECT id FROM users WHERE email = $1", Args: []any{"hernan_rm@outlook.es"}})).
+				Where(dafi.Filter{Field: "id", Value: 7}),
+			want: Result{
+				Sql:  "UPDATE posts SET title = $1, views = views + $2, updated_at = now(), category_id = DEFAULT, author_id = (SELECT id FROM users WHERE email = $3) WHERE id = $4",
+				Args: []any{"Hello", 1, "hernan_rm@outlook.es", 7},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "update from other table",
+			query: Update("orders").Set("status", "shipped").From("shipments").Where(dafi.Filter{Field: "orders.id", Value: ColumnRef("shipments.order_id")}, dafi.Filter{Field: "shipments.carrier", Value: "dhl"}),
+			want: Result{
+				Sql:  "UPDATE orders SET status = $1 FROM shipments WHERE orders.id = shipments.order_id AND shipments.carrier = $2",
+				Args: []any{"shipped", "dhl"},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "update from subquery",
+			query: Update("orders o").Set("total", ColumnRef("t.total")).FromSubquery(Result{Sql: "SELECT order_id, sum(price) AS total FROM items WHERE price > $1 GROUP BY order_id", Args: []any{0}}, "t").Where(dafi.Filter{Field: "o.id", Value: ColumnRef("t.order_id")}),
+			want: Result{
+				Sql:  "UPDATE orders o SET total = t.total FROM (SELECT order_id, sum(price) AS total FROM items WHERE price > $1 GROUP BY order_id) AS t WHERE o.id = t.order_id",
+				Args: []any{0},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "update join with mysql dialect",
+			query: Update("orders").Set("status", "shipped").FromSubquery(Result{Sql: "SELECT order_id FROM shipments WHERE carrier = $1", Args: []any{"dhl"}}, "s").Where(dafi.Filter{Field: "orders.id", Value: ColumnRef("s.order_id")}, dafi.Filter{Field: "orders.status", Value: "paid"}).WithDialect(MySQL),
+			want: Result{
+				Sql:  "UPDATE orders JOIN (SELECT order_id FROM shipments WHERE carrier = ?) AS s SET status = ? WHERE orders.id = s.order_id AND orders.status = ?",
+				Args: []any{"dhl", "shipped", "paid"},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "update with version",
+			query: Update("accounts").Set("balance", 100).Where(dafi.Filter{Field: "id", Value: 1, ChainingKey: dafi.Or}, dafi.Filter{Field: "email", Value: "hernan_rm@outlook.es"}).WithVersion("version", 3),
+			want: Result{
+				Sql:  "UPDATE accounts SET balance = $1, version = accounts.version + 1 WHERE (id = $2 OR email = $3) AND accounts.version = $4",
+				Args: []any{100, 1, "hernan_rm@outlook.es", 3},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "error version without filters",
+			query:   Update("accounts").Set("balance", 100).WithVersion("version", 3),
+			want:    Result{},
+			wantErr: true,
+		},
+		{
+			name:    "error update with match all filter chained by or",
+			query:   Update("employees").Set("salary", 4000).Where(dafi.Filter{Field: "id", Value: 1, ChainingKey: dafi.Or}, dafi.Filter{Field: "role", Operator: dafi.NotIn, Value: []string{}}),
+			want:    Result{},
+			wantErr: true,
+		},
+		{
+			name:  "update from with version",
+			query: Update("accounts a").Set("balance", ColumnRef("t.balance")).From("transfers t").Where(dafi.Filter{Field: "a.id", Value: ColumnRef("t.account_id")}).WithVersion("version", 3),
+			want: Result{
+				Sql:  "UPDATE accounts a SET balance = t.balance, version = a.version + 1 FROM transfers t WHERE (a.id = t.account_id) AND a.version = $1",
+				Args: []any{3},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "error returning with mysql dialect",
+			query:   Update("employees").Set("salary", 4000).Where(dafi.Filter{Field: "id", Value: 1}).Returning("id").WithDialect(MySQL),
+			want:    Result{},
+			wantErr: true,
+		},
+		{
+			name:  "update placeholder columns with version",
+			query: Update("accounts").WithColumns("name", "balance").WithVersion("version", 3).Where(dafi.Filter{Field: "id", Value: 1}),
+			want: Result{
+				Sql:  "UPDATE accounts SET name = $1, balance = $2, version = accounts.version + 1 WHERE (id = $3) AND accounts.version = $4",
+				Args: []any{1, 3},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "error every optional value unset with version",
+			query:   Update("accounts").WithColumns("name").WithValues(Optional[string]{}).WithVersion("version", 3).Where(dafi.Filter{Field: "id", Value: 1}),
+			want:    Result{},
+			wantErr: true,
+		},
+		{
+			name:    "error update without filters",
+			query:   Update("employees").WithColumns("salary").WithValues(4000),
+			want:    Result{},
+			wantErr: true,
+		},
+		{
+			name:    "error every optional value unset",
+			query:   Update("employees").WithColumns("salary").WithValues(Optional[int]{}),
+			want:    Result{},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,32 @@
+package sqlcraft
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/techforge-lat/errortrace/v2"
+	"github.com/techforge-lat/errortrace/v2/errtype"
+)
+
+// CodeConflict is the errortrace code of the errors caused by a concurrent modification
+const CodeConflict errtype.Code = "conflict"
+
+var ErrVersionConflict = errors.New("version conflict")
+
+// CheckVersion returns a conflict error when an UpdateQuery built WithVersion did not affect any row,
+// which means the row was modified (or deleted) since its version was read
+func CheckVersion(result sql.Result) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errortrace.OnError(err).WithCode(errtype.InternalError)
+	}
+
+	if rowsAffected == 0 {
+		return errortrace.
+			OnError(ErrVersionConflict).
+			WithCode(CodeConflict).
+			WithMessage("the record was modified by someone else, reload it and try again")
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package sqlcraft
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/techforge-lat/errortrace/v2"
+)
+
+type fakeSQLResult struct {
+	rowsAffected int64
+	err          error
+}
+
+func (f fakeSQLResult) LastInsertId() (int64, error) {
+	return 0, nil
+}
+
+func (f fakeSQLResult) RowsAffected() (int64, error) {
+	return f.rowsAffected, f.err
+}
+
+func TestCheckVersion(t *testing.T) {
+	errDriver := errors.New("driver error")
+
+	tests := []struct {
+		name    string
+		result  fakeSQLResult
+		wantErr error
+	}{
+		{name: "row updated", result: fakeSQLResult{rowsAffected: 1}},
+		{name: "conflict", result: fakeSQLResult{rowsAffected: 0}, wantErr: ErrVersionConflict},
+		{name: "rows affected error", result: fakeSQLResult{err: errDriver}, wantErr: errDriver},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckVersion(tt.result)
+			if !errortrace.Is(err, tt.wantErr) {
+				t.Errorf("CheckVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
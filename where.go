@@ -1,8 +1,10 @@
 package sqlcraft
 
 import (
+	"database/sql/driver"
 	"errors"
 	"fmt"
+	"reflect"
 	"strconv"
 	"strings"
 
@@ -14,6 +16,24 @@ import (
 var (
 	ErrInvalidOperator  = errors.New("invalid dafi operator")
 	ErrInvalidFieldName = errors.New("invalid field name")
+	ErrEmptyInList      = errors.New("empty IN list")
+	ErrMissingFilters   = errors.New("missing filters, use AllowFullTable to affect every row")
+)
+
+const (
+	matchNothingSQL = "1 = 0"
+	matchAllSQL     = "1 = 1"
+)
+
+// EmptyInBehavior defines how an IN or NOT IN filter with an empty list is built
+type EmptyInBehavior string
+
+const (
+	// EmptyInDefault matches nothing for IN and everything for NOT IN
+	EmptyInDefault      EmptyInBehavior = ""
+	EmptyInMatchNothing EmptyInBehavior = "match_nothing"
+	EmptyInMatchAll     EmptyInBehavior = "match_all"
+	EmptyInError        EmptyInBehavior = "error"
 )
 
 var psqlOperatorByDafiOperator = map[dafi.FilterOperator]string{
@@ -34,34 +54,182 @@ var psqlOperatorByDafiOperator = map[dafi.FilterOperator]string{
 	dafi.Default:        "",
 }
 
+// WhereOptions holds the rules applied to the filters before building the WHERE sentence
+type WhereOptions struct {
+	// SQLColumnByDomainField maps domain field names to sql column names
+	SQLColumnByDomainField map[string]string
+	// FieldPolicies restricts and maps the domain fields a client can filter by,
+	// when present it takes precedence over SQLColumnByDomainField
+	FieldPolicies FieldPolicies
+	// ColumnTypes is used to coerce string values into the type of their sql column
+	ColumnTypes ColumnTypes
+	// EmptyIn defines how an IN filter with an empty list is built, by default it matches nothing
+	EmptyIn EmptyInBehavior
+	// EmptyNotIn defines how a NOT IN filter with an empty list is built, by default it matches everything
+	EmptyNotIn EmptyInBehavior
+}
+
 // WhereSafe maps domain field names to sql column names,
 // if a filter with an unknow domain field name is found it will return an error
 func WhereSafe(initialArgCount int, sqlColumnByDomainField map[string]string, filters ...dafi.Filter) (Result, error) {
-	if len(sqlColumnByDomainField) > 0 {
-		for i, filter := range filters {
-			sqlColumnName, ok := sqlColumnByDomainField[string(filter.Field)]
-			if !ok {
-				return Result{}, errortrace.
-					OnError(ErrInvalidFieldName).
-					WithCode(errtype.UnprocessableEntity).
-					WithMessage(fmt.Sprintf("field %q not found", filter.Field))
+	return WhereWithOptions(initialArgCount, WhereOptions{SQLColumnByDomainField: sqlColumnByDomainField}, filters...)
+}
+
+// WhereWithOptions validates and maps the filters with the given options before building the WHERE sentence,
+// the given filters are never modified
+func WhereWithOptions(initialArgCount int, options WhereOptions, filters ...dafi.Filter) (Result, error) {
+	result, _, err := whereWithOptions(initialArgCount, options, filters...)
+
+	return result, err
+}
+
+// whereWithOptions is WhereWithOptions that also returns the number of filters that restrict the rows,
+// filters that match everything (like an empty NOT IN) are not counted, and none is counted
+// when one of them is chained with OR because the whole sentence may match every row
+func whereWithOptions(initialArgCount int, options WhereOptions, filters ...dafi.Filter) (Result, int, error) {
+	mappedFilters := make(dafi.Filters, len(filters))
+	copy(mappedFilters, filters)
+
+	for i, filter := range mappedFilters {
+		value, err := options.coerce(filter)
+		if err != nil {
+			return Result{}, 0, err
+		}
+		filter.Value = value
+
+		sqlColumnName, err := options.filterColumn(filter)
+		if err != nil {
+			return Result{}, 0, err
+		}
+
+		if ref, ok := value.(ColumnRef); ok {
+			value, err = options.refColumn(ref)
+			if err != nil {
+				return Result{}, 0, err
 			}
+		}
+
+		mappedFilters[i].Field = dafi.FilterField(sqlColumnName)
+		mappedFilters[i].Value = value
+	}
+
+	return where(initialArgCount, options, mappedFilters...)
+}
+
+// sqlColumn returns the sql column of a domain field without validating it
+func (o WhereOptions) sqlColumn(field string) string {
+	if policy, ok := o.FieldPolicies[field]; ok {
+		return policy.column(field)
+	}
+
+	if sqlColumnName, ok := o.SQLColumnByDomainField[field]; ok {
+		return sqlColumnName
+	}
+
+	return field
+}
+
+// columnType returns the type used to coerce the values of a domain field,
+// the one of its policy or the one of its sql column
+func (o WhereOptions) columnType(field string) (ColumnType, bool) {
+	if policy, ok := o.FieldPolicies[field]; ok && policy.ColumnType != "" {
+		return policy.ColumnType, true
+	}
+
+	columnType, ok := o.ColumnTypes[o.sqlColumn(field)]
 
-			filters[i].Field = dafi.FilterField(sqlColumnName)
+	return columnType, ok
+}
+
+// coerce converts the filter value into the type of its sql column, a column reference is kept as is
+func (o WhereOptions) coerce(filter dafi.Filter) (any, error) {
+	if _, ok := filter.Value.(ColumnRef); ok {
+		return filter.Value, nil
+	}
+
+	columnType, ok := o.columnType(string(filter.Field))
+	if !ok {
+		return filter.Value, nil
+	}
+
+	switch filter.Operator {
+	case dafi.In, dafi.NotIn:
+		if filter.Value == nil {
+			return nil, nil
+		}
+
+		return CoerceValues(columnType, filter.Value)
+	case dafi.Contains, dafi.NotContains, dafi.Is, dafi.IsNot, dafi.IsNull, dafi.IsNotNull, dafi.Default:
+		return filter.Value, nil
+	default:
+		return CoerceValue(columnType, filter.Value)
+	}
+}
+
+// emptyIn returns the sql sentence used in place of an IN or NOT IN filter with an empty list
+func (o WhereOptions) emptyIn(column string, operator dafi.FilterOperator) (string, error) {
+	behavior := o.EmptyIn
+	if behavior == EmptyInDefault {
+		behavior = EmptyInMatchNothing
+	}
+
+	if operator == dafi.NotIn {
+		behavior = o.EmptyNotIn
+		if behavior == EmptyInDefault {
+			behavior = EmptyInMatchAll
 		}
 	}
 
-	return Where(initialArgCount, filters...)
+	switch behavior {
+	case EmptyInMatchNothing:
+		return matchNothingSQL, nil
+	case EmptyInMatchAll:
+		return matchAllSQL, nil
+	default:
+		return "", errortrace.
+			OnError(ErrEmptyInList).
+			WithCode(errtype.UnprocessableEntity).
+			WithMessage(fmt.Sprintf("field %q requires at least one value", column))
+	}
+}
+
+func (o WhereOptions) filterColumn(filter dafi.Filter) (string, error) {
+	if len(o.FieldPolicies) > 0 {
+		return o.FieldPolicies.FilterColumn(filter)
+	}
+
+	if len(o.SQLColumnByDomainField) == 0 {
+		return string(filter.Field), nil
+	}
+
+	sqlColumnName, ok := o.SQLColumnByDomainField[string(filter.Field)]
+	if !ok {
+		return "", errortrace.
+			OnError(ErrInvalidFieldName).
+			WithCode(errtype.UnprocessableEntity).
+			WithMessage(fmt.Sprintf("field %q not found", filter.Field))
+	}
+
+	return sqlColumnName, nil
 }
 
 // Where returns a WHERE sql sentence and if an invalid operator is found, it will return an error
 func Where(initialArgCount int, filters ...dafi.Filter) (Result, error) {
+	result, _, err := where(initialArgCount, WhereOptions{}, filters...)
+
+	return result, err
+}
+
+func where(initialArgCount int, options WhereOptions, filters ...dafi.Filter) (Result, int, error) {
 	if len(filters) == 0 {
-		return Result{}, nil
+		return Result{}, 0, nil
 	}
 
 	builder := strings.Builder{}
 	args := []any{}
+	predicates := 0
+	orMatchesAll := false
+	previousChainingKey := dafi.FilterChainingKey("")
 
 	builder.WriteString(" WHERE ")
 	for i, filter := range filters {
@@ -79,27 +247,57 @@ func Where(initialArgCount int, filters ...dafi.Filter) (Result, error) {
 
 		operator, ok := psqlOperatorByDafiOperator[filter.Operator]
 		if !ok {
-			return Result{}, errortrace.
+			return Result{}, 0, errortrace.
 				OnError(errors.Join(fmt.Errorf("operator %q not found", filter.Operator), ErrInvalidOperator)).
 				WithCode(errtype.UnprocessableEntity).
 				WithMessage(fmt.Sprintf("operator %q not found", filter.Operator))
 		}
 
+		matchesAll := false
 		switch filter.Operator {
 		case dafi.In, dafi.NotIn:
-			inResult := In(filter.Value, len(args)+1+initialArgCount)
-			if inResult.Sql == "" {
-				continue
+			if _, ok := filter.Value.(ColumnRef); ok {
+				return Result{}, 0, errortrace.
+					OnError(ErrInvalidColumnRef).
+					WithCode(errtype.UnprocessableEntity).
+					WithMessage(fmt.Sprintf("operator %q does not accept a column reference", filter.Operator))
 			}
 
-			builder.WriteString(string(filter.Field))
-			builder.WriteString(" ")
-			builder.WriteString(operator)
+			values, hasNull := withoutNulls(inValues(filter.Value))
+			inResult := In(values, len(args)+1+initialArgCount)
+			if inResult.Sql == "" && !hasNull {
+				emptyInSQL, err := options.emptyIn(string(filter.Field), filter.Operator)
+				if err != nil {
+					return Result{}, 0, err
+				}
 
-			builder.WriteString(" ")
-			builder.WriteString(inResult.Sql)
+				builder.WriteString(emptyInSQL)
+				matchesAll = emptyInSQL == matchAllSQL
+
+				break
+			}
+
+			writeIn(&builder, string(filter.Field), filter.Operator, inResult, hasNull)
 
 			args = append(args, inResult.Args...)
+		case dafi.Equal, dafi.NotEqual:
+			if !isNull(filter.Value) {
+				var err error
+				args, err = writeComparison(&builder, string(filter.Field), operator, filter.Value, args, initialArgCount)
+				if err != nil {
+					return Result{}, 0, err
+				}
+
+				break
+			}
+
+			builder.WriteString(string(filter.Field))
+			builder.WriteString(" ")
+			if filter.Operator == dafi.Equal {
+				builder.WriteString(psqlOperatorByDafiOperator[dafi.IsNull])
+			} else {
+				builder.WriteString(psqlOperatorByDafiOperator[dafi.IsNotNull])
+			}
 		case dafi.Is, dafi.IsNot, dafi.IsNull, dafi.IsNotNull:
 			builder.WriteString(string(filter.Field))
 			builder.WriteString(" ")
@@ -107,13 +305,19 @@ func Where(initialArgCount int, filters ...dafi.Filter) (Result, error) {
 		case dafi.Default:
 			builder.WriteString(string(filter.Field))
 		default:
-			builder.WriteString(string(filter.Field))
-			builder.WriteString(" ")
-			builder.WriteString(operator)
-			builder.WriteString(" $")
-			builder.WriteString(strconv.Itoa(len(args) + 1 + initialArgCount))
+			var err error
+			args, err = writeComparison(&builder, string(filter.Field), operator, filter.Value, args, initialArgCount)
+			if err != nil {
+				return Result{}, 0, err
+			}
+		}
 
-			args = append(args, filter.Value)
+		if !matchesAll {
+			predicates++
+		}
+
+		if matchesAll && (filter.ChainingKey == dafi.Or || previousChainingKey == dafi.Or) {
+			orMatchesAll = true
 		}
 
 		if i < len(filters)-1 && filter.ChainingKey == "" {
@@ -133,10 +337,143 @@ func Where(initialArgCount int, filters ...dafi.Filter) (Result, error) {
 			builder.WriteString(string(filter.ChainingKey))
 			builder.WriteString(" ")
 		}
+
+		previousChainingKey = filter.ChainingKey
+	}
+
+	if orMatchesAll {
+		predicates = 0
 	}
 
 	return Result{
 		Sql:  strings.TrimRight(builder.String(), " "),
 		Args: args,
-	}, nil
+	}, predicates, nil
+}
+
+// writeComparison writes a comparison against a bound argument or a column reference
+// and returns the args with the bound value
+func writeComparison(builder *strings.Builder, column, operator string, value any, args []any, initialArgCount int) ([]any, error) {
+	builder.WriteString(column)
+	builder.WriteString(" ")
+	builder.WriteString(operator)
+	builder.WriteString(" ")
+
+	if ref, ok := value.(ColumnRef); ok {
+		if err := ref.validate(); err != nil {
+			return nil, err
+		}
+
+		builder.WriteString(string(ref))
+
+		return args, nil
+	}
+
+	builder.WriteString("$")
+	builder.WriteString(strconv.Itoa(len(args) + 1 + initialArgCount))
+
+	return append(args, value), nil
+}
+
+// writeIn writes an IN or NOT IN sentence, when the list had null values
+// it adds an IS NULL (or IS NOT NULL) branch because NULL never matches an IN list
+func writeIn(builder *strings.Builder, column string, operator dafi.FilterOperator, inResult Result, hasNull bool) {
+	nullOperator, nullChainingKey := psqlOperatorByDafiOperator[dafi.IsNull], "OR"
+	if operator == dafi.NotIn {
+		nullOperator, nullChainingKey = psqlOperatorByDafiOperator[dafi.IsNotNull], "AND"
+	}
+
+	if !hasNull {
+		builder.WriteString(column)
+		builder.WriteString(" ")
+		builder.WriteString(psqlOperatorByDafiOperator[operator])
+		builder.WriteString(" ")
+		builder.WriteString(inResult.Sql)
+
+		return
+	}
+
+	if inResult.Sql == "" {
+		builder.WriteString(column)
+		builder.WriteString(" ")
+		builder.WriteString(nullOperator)
+
+		return
+	}
+
+	builder.WriteString("(")
+	builder.WriteString(column)
+	builder.WriteString(" ")
+	builder.WriteString(psqlOperatorByDafiOperator[operator])
+	builder.WriteString(" ")
+	builder.WriteString(inResult.Sql)
+	builder.WriteString(" ")
+	builder.WriteString(nullChainingKey)
+	builder.WriteString(" ")
+	builder.WriteString(column)
+	builder.WriteString(" ")
+	builder.WriteString(nullOperator)
+	builder.WriteString(")")
+}
+
+// isNull reports whether the value is stored as NULL: nil, a nil pointer
+// or a driver.Valuer (like sql.NullString) whose value is nil
+func isNull(value any) bool {
+	if value == nil {
+		return true
+	}
+
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Pointer && rv.IsNil() {
+		return true
+	}
+
+	if valuer, ok := value.(driver.Valuer); ok {
+		v, err := valuer.Value()
+
+		return err == nil && v == nil
+	}
+
+	return false
+}
+
+func withoutNulls(values []any) ([]any, bool) {
+	hasNull := false
+	notNullValues := make([]any, 0, len(values))
+	for _, value := range values {
+		if isNull(value) {
+			hasNull = true
+			continue
+		}
+
+		notNullValues = append(notNullValues, value)
+	}
+
+	return notNullValues, hasNull
+}
+
+// andScopes appends scope predicates (like a version check) to a WHERE sentence whose args start after argCount,
+// the filters are parenthesized so their OR chaining keys can't bypass the scopes
+func andScopes(where Result, argCount int, scopes ...Expr) Result {
+	if len(scopes) == 0 {
+		return where
+	}
+
+	scopeSQL, scopeArgs := joinExprs(scopes, argCount+len(where.Args))
+
+	builder := strings.Builder{}
+	builder.WriteString(" WHERE ")
+
+	if where.Sql != "" {
+		builder.WriteString("(")
+		builder.WriteString(strings.TrimPrefix(where.Sql, " WHERE "))
+		builder.WriteString(") AND ")
+	}
+
+	builder.WriteString(strings.Join(scopeSQL, " AND "))
+
+	return Result{
+		Sql:  builder.String(),
+		Args: append(append([]any{}, where.Args...), scopeArgs...),
+	}
 }
@@ -1,8 +1,10 @@
 package sqlcraft
 
 import (
+	"database/sql"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/techforge-lat/dafi/v2"
 )
@@ -235,6 +237,134 @@ func TestWhere(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "equal nil",
+			args: args{
+				filters: dafi.Filters{
+					dafi.Filter{
+						Field:    "deleted_at",
+						Operator: dafi.Equal,
+						Value:    nil,
+					},
+				},
+			},
+			want: Result{
+				Sql:  " WHERE deleted_at IS NULL",
+				Args: []any{},
+			},
+			wantErr: false,
+		},
+		{
+			name: "not equal typed nil pointer",
+			args: args{
+				filters: dafi.Filters{
+					dafi.Filter{
+						Field:    "deleted_at",
+						Operator: dafi.NotEqual,
+						Value:    (*time.Time)(nil),
+					},
+					dafi.Filter{
+						Field:    "email",
+						Operator: dafi.Equal,
+						Value:    "hernan_rm@outlook.es",
+					},
+				},
+			},
+			want: Result{
+				Sql:  " WHERE deleted_at IS NOT NULL AND email = $1",
+				Args: []any{"hernan_rm@outlook.es"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "equal invalid sql null",
+			args: args{
+				filters: dafi.Filters{
+					dafi.Filter{
+						Field: "nickname",
+						Value: sql.NullString{},
+					},
+				},
+			},
+			want: Result{
+				Sql:  " WHERE nickname IS NULL",
+				Args: []any{},
+			},
+			wantErr: false,
+		},
+		{
+			name: "in operator with nil",
+			args: args{
+				filters: dafi.Filters{
+					dafi.Filter{
+						Field:    "nickname",
+						Operator: dafi.In,
+						Value:    []any{"hernan", nil, "brownie"},
+					},
+				},
+			},
+			want: Result{
+				Sql:  " WHERE (nickname IN ($1, $2) OR nickname IS NULL)",
+				Args: []any{"hernan", "brownie"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "not in operator with nil",
+			args: args{
+				filters: dafi.Filters{
+					dafi.Filter{
+						Field:    "nickname",
+						Operator: dafi.NotIn,
+						Value:    []*string{nil},
+					},
+				},
+			},
+			want: Result{
+				Sql:  " WHERE nickname IS NOT NULL",
+				Args: []any{},
+			},
+			wantErr: false,
+		},
+		{
+			name: "empty in operator matches nothing",
+			args: args{
+				filters: dafi.Filters{
+					dafi.Filter{
+						Field:    "id",
+						Operator: dafi.In,
+						Value:    []uint{},
+					},
+					dafi.Filter{
+						Field:    "email",
+						Operator: dafi.Equal,
+						Value:    "hernan_rm@outlook.es",
+					},
+				},
+			},
+			want: Result{
+				Sql:  " WHERE 1 = 0 AND email = $1",
+				Args: []any{"hernan_rm@outlook.es"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "empty not in operator matches everything",
+			args: args{
+				filters: dafi.Filters{
+					dafi.Filter{
+						Field:    "id",
+						Operator: dafi.NotIn,
+						Value:    []uint{},
+					},
+				},
+			},
+			want: Result{
+				Sql:  " WHERE 1 = 1",
+				Args: []any{},
+			},
+			wantErr: false,
+		},
 		{
 			name: "invalid operator",
 			args: args{
@@ -263,3 +393,47 @@ func TestWhere(t *testing.T) {
 		})
 	}
 }
+
+func TestWhereWithOptions_EmptyIn(t *testing.T) {
+	tests := []struct {
+		name    string
+		options WhereOptions
+		filter  dafi.Filter
+		want    Result
+		wantErr bool
+	}{
+		{
+			name:    "in matches everything",
+			options: WhereOptions{EmptyIn: EmptyInMatchAll},
+			filter:  dafi.Filter{Field: "id", Operator: dafi.In, Value: ""},
+			want:    Result{Sql: " WHERE 1 = 1", Args: []any{}},
+		},
+		{
+			name:    "not in matches nothing",
+			options: WhereOptions{EmptyNotIn: EmptyInMatchNothing},
+			filter:  dafi.Filter{Field: "id", Operator: dafi.NotIn, Value: []int{}},
+			want:    Result{Sql: " WHERE 1 = 0", Args: []any{}},
+		},
+		{
+			name:    "in returns an error",
+			options: WhereOptions{EmptyIn: EmptyInError},
+			filter:  dafi.Filter{Field: "id", Operator: dafi.In, Value: nil},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := WhereWithOptions(0, tt.options, tt.filter)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("WhereWithOptions() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("WhereWithOptions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}